@@ -6,12 +6,15 @@
 package gomnia
 
 import (
+	"bytes"
+	"context"
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -19,7 +22,6 @@ import (
 
 	"github.com/alex-berlin-tv/gomnia/enum"
 	"github.com/alex-berlin-tv/gomnia/params"
-	"github.com/sirupsen/logrus"
 )
 
 // The API of omnia differentiates API endpoints into multiple classes. The URL-structure of
@@ -27,60 +29,115 @@ import (
 // provides a sane way of defining the different URL composition schemas.
 type apiType interface {
 	// Build the correct URL based on the endpoint type.
-	UrlBuilder(domainId string, streamType enum.StreamType, operation, args, tail string) string
+	UrlBuilder(baseUrl, domainId string, streamType enum.StreamType, operation, args, tail string) string
+	// The class of the API the endpoint belongs to.
+	Class() ApiClass
 }
 
+// The classes of endpoints provided by the omnia API.
+type ApiClass string
+
+const (
+	// The Media API used to query media items.
+	MediaApiClass = ApiClass("media")
+	// The Management API used to alter items and the domain.
+	ManagementApiClass = ApiClass("management")
+	// The Domain Data API providing information on the domain.
+	DomainApiClass = ApiClass("domain")
+	// The System API providing general information.
+	SystemApiClass = ApiClass("system")
+)
+
 type mediaApiType struct{}
 
-func (t mediaApiType) UrlBuilder(domainId string, streamType enum.StreamType, operation, args, tail string) string {
+func (t mediaApiType) Class() ApiClass {
+	return MediaApiClass
+}
+
+func (t mediaApiType) UrlBuilder(baseUrl, domainId string, streamType enum.StreamType, operation, args, tail string) string {
 	return fmt.Sprintf(
-		"https://api.nexx.cloud/v3.1/%s/%s/%s%s",
-		domainId, streamType, operation, args,
+		"%s/%s/%s/%s%s",
+		baseUrl, domainId, streamType, operation, args,
 	)
 }
 
 type managementApiType struct{}
 
-func (t managementApiType) UrlBuilder(domainId string, streamType enum.StreamType, operation, args, tail string) string {
+func (t managementApiType) Class() ApiClass {
+	return ManagementApiClass
+}
+
+func (t managementApiType) UrlBuilder(baseUrl, domainId string, streamType enum.StreamType, operation, args, tail string) string {
 	return fmt.Sprintf(
-		"https://api.nexx.cloud/v3.1/%s/manage/%s%s/%s",
-		domainId, streamType, args, operation,
+		"%s/%s/manage/%s%s/%s",
+		baseUrl, domainId, streamType, args, operation,
 	)
 }
 
-type connectManagementApiType struct{}
+// Management API operations on an item taking a further argument after the
+// operation, like the show of connectshow or the variant of the cover operations.
+type itemTailManagementApiType struct{}
 
-func (t connectManagementApiType) UrlBuilder(domainId string, streamType enum.StreamType, operation, args, tail string) string {
+func (t itemTailManagementApiType) Class() ApiClass {
+	return ManagementApiClass
+}
+
+func (t itemTailManagementApiType) UrlBuilder(baseUrl, domainId string, streamType enum.StreamType, operation, args, tail string) string {
 	return fmt.Sprintf(
-		"https://api.nexx.cloud/v3.1/%s/manage/%s%s/%s/%s",
-		domainId, streamType, args, operation, tail,
+		"%s/%s/manage/%s%s/%s/%s",
+		baseUrl, domainId, streamType, args, operation, tail,
 	)
 }
 
 type domainDataApiType struct{}
 
-func (t domainDataApiType) UrlBuilder(domainId string, streamType enum.StreamType, operation, args, tail string) string {
+func (t domainDataApiType) Class() ApiClass {
+	return DomainApiClass
+}
+
+func (t domainDataApiType) UrlBuilder(baseUrl, domainId string, streamType enum.StreamType, operation, args, tail string) string {
 	return fmt.Sprintf(
-		"https://api.nexx.cloud/v3.1/%s/domain/%s",
-		domainId, operation,
+		"%s/%s/domain/%s",
+		baseUrl, domainId, operation,
 	)
 }
 
 type uploadLinkManagementApiType struct{}
 
-func (t uploadLinkManagementApiType) UrlBuilder(domainId string, streamType enum.StreamType, operation, args, tail string) string {
+func (t uploadLinkManagementApiType) Class() ApiClass {
+	return ManagementApiClass
+}
+
+func (t uploadLinkManagementApiType) UrlBuilder(baseUrl, domainId string, streamType enum.StreamType, operation, args, tail string) string {
+	return fmt.Sprintf(
+		"%s/%s/manage/uploadlinks/%s",
+		baseUrl, domainId, operation,
+	)
+}
+
+type uploadManagementApiType struct{}
+
+func (t uploadManagementApiType) Class() ApiClass {
+	return ManagementApiClass
+}
+
+func (t uploadManagementApiType) UrlBuilder(baseUrl, domainId string, streamType enum.StreamType, operation, args, tail string) string {
 	return fmt.Sprintf(
-		"https://api.nexx.cloud/v3.1/%s/manage/uploadlinks/%s",
-		domainId, operation,
+		"%s/%s/manage/%s/upload/%s%s",
+		baseUrl, domainId, streamType, operation, args,
 	)
 }
 
 type systemApiType struct{}
 
-func (t systemApiType) UrlBuilder(domainId string, streamType enum.StreamType, operation, args, tail string) string {
+func (t systemApiType) Class() ApiClass {
+	return SystemApiClass
+}
+
+func (t systemApiType) UrlBuilder(baseUrl, domainId string, streamType enum.StreamType, operation, args, tail string) string {
 	return fmt.Sprintf(
-		"https://api.nexx.cloud/v3.1/%s/system/%s%s",
-		domainId, operation, args,
+		"%s/%s/system/%s%s",
+		baseUrl, domainId, operation, args,
 	)
 }
 
@@ -93,8 +150,10 @@ type omniaHeader struct {
 	xRequestToken string
 }
 
-func newOmniaHeader(operation, domainId, apiSecret, sessionId string) omniaHeader {
-	logrus.Debugf("hash source: md5(%s+%s+API_SECRET)", operation, domainId)
+func newOmniaHeader(logger Logger, operation, domainId, apiSecret, sessionId string) omniaHeader {
+	if logger.Enabled(DebugLevel) {
+		logger.Debug(fmt.Sprintf("hash source: md5(%s+%s+API_SECRET)", operation, domainId), nil)
+	}
 	signature := md5.Sum([]byte(fmt.Sprintf("%s%s%s", operation, domainId, apiSecret)))
 	return omniaHeader{
 		xRequestCid:   sessionId,
@@ -114,29 +173,63 @@ type Client struct {
 	ApiSecret string `json:"api_secret"`
 	// Named »Management API Session« in the domain detail view.
 	SessionId string `json:"session_id"`
+
+	httpClient *http.Client
+	baseUrl    string
+	timeout    time.Duration
+	userAgent  string
+	retry      RetryPolicy
+	limiter    *RateLimiter
+	limiters   map[ApiClass]*RateLimiter
+	middleware []Middleware
+	tracer     Tracer
+
+	pagingRestarts int
+	logger         Logger
+	redactedFields map[string]struct{}
+	noRedaction    bool
+
+	attributes      *attributeCache
+	validateUpdates bool
 }
 
 // Returns a new Omnia instance. For mor information on how to obtain the needed
-// parameters please refer to the documentation of the [Client] type.
-func NewClient(domainId string, apiSecret string, sessionId string) Client {
-	return Client{
-		DomainId:  domainId,
-		ApiSecret: apiSecret,
-		SessionId: sessionId,
+// parameters please refer to the documentation of the [Client] type. The behavior
+// of the client can be altered by passing any number of [ClientOption]s. Example,
+// use a staging gateway and allow requests to take up to one minute:
+//
+//	client := omnia.NewClient("23", "Secret", "42",
+//		omnia.WithBaseURL("https://staging.example.com/v3.1"),
+//		omnia.WithTimeout(time.Minute),
+//	)
+func NewClient(domainId string, apiSecret string, sessionId string, opts ...ClientOption) Client {
+	rsl := Client{
+		DomainId:   domainId,
+		ApiSecret:  apiSecret,
+		SessionId:  sessionId,
+		attributes: newAttributeCache(),
 	}
+	for _, opt := range opts {
+		opt(&rsl)
+	}
+	return rsl
 }
 
-// Reads an Omnia instance from a json file.
-func OmniaFromFile(path string) Client {
+// Reads an Omnia instance from a json file. The given options are applied to the
+// client after reading the file.
+func OmniaFromFile(path string, opts ...ClientOption) (Client, error) {
 	file, err := os.ReadFile(path)
 	if err != nil {
-		logrus.Fatal(err)
+		return Client{}, err
 	}
-	rsl := Client{}
+	rsl := Client{attributes: newAttributeCache()}
 	if err := json.Unmarshal([]byte(file), &rsl); err != nil {
-		logrus.Fatal(err)
+		return Client{}, fmt.Errorf("invalid client file %s, %w", path, err)
 	}
-	return rsl
+	for _, opt := range opts {
+		opt(&rsl)
+	}
+	return rsl, nil
 }
 
 // Return a item of a given streamtype by it's id. Example, get the audio item with
@@ -148,41 +241,81 @@ func OmniaFromFile(path string) Client {
 //		"addPublishingDetails": 1,
 //	})
 func (o Client) ById(streamType enum.StreamType, id int, parameters params.QueryParameters) (*Response[MediaResultItem], error) {
-	return Call(o, "get", streamType, "byid", []string{strconv.Itoa(id)}, parameters, 1, Response[MediaResultItem]{})
+	return o.ByIdCtx(context.Background(), streamType, id, parameters)
+}
+
+// Same as [Client.ById] but the request is bound to the given context.
+func (o Client) ByIdCtx(ctx context.Context, streamType enum.StreamType, id int, parameters params.QueryParameters) (*Response[MediaResultItem], error) {
+	return CallCtx(ctx, o, "get", streamType, "byid", []string{strconv.Itoa(id)}, parameters, 0, Response[MediaResultItem]{})
 }
 
 // Return a item of a given streamtype by it's global id.
 func (o Client) ByGlobalId(streamType enum.StreamType, globalId int, parameters params.QueryParameters) (*Response[MediaResultItem], error) {
-	return Call(o, "get", streamType, "byglobalid", []string{strconv.Itoa(globalId)}, parameters, 1, Response[MediaResultItem]{})
+	return o.ByGlobalIdCtx(context.Background(), streamType, globalId, parameters)
+}
+
+// Same as [Client.ByGlobalId] but the request is bound to the given context.
+func (o Client) ByGlobalIdCtx(ctx context.Context, streamType enum.StreamType, globalId int, parameters params.QueryParameters) (*Response[MediaResultItem], error) {
+	return CallCtx(ctx, o, "get", streamType, "byglobalid", []string{strconv.Itoa(globalId)}, parameters, 0, Response[MediaResultItem]{})
 }
 
 // Return a item of a given streamtype by it's hash.
 func (o Client) ByHash(streamType enum.StreamType, hash string, parameters params.QueryParameters) (*Response[MediaResultItem], error) {
-	return Call(o, "get", streamType, "byhash", []string{hash}, parameters, 1, Response[MediaResultItem]{})
+	return o.ByHashCtx(context.Background(), streamType, hash, parameters)
+}
+
+// Same as [Client.ByHash] but the request is bound to the given context.
+func (o Client) ByHashCtx(ctx context.Context, streamType enum.StreamType, hash string, parameters params.QueryParameters) (*Response[MediaResultItem], error) {
+	return CallCtx(ctx, o, "get", streamType, "byhash", []string{hash}, parameters, 0, Response[MediaResultItem]{})
 }
 
 // Return a item of a given streamtype by it's reference number.
-func (o Client) ByRefNr(streamType enum.StreamType, reference string, parameters params.QueryParameters) (*Response[any], error) {
-	return Call(o, "get", streamType, "byrefnr", []string{reference}, parameters, 1, Response[any]{})
+func (o Client) ByRefNr(streamType enum.StreamType, reference string, parameters params.QueryParameters) (*Response[MediaResultItem], error) {
+	return o.ByRefNrCtx(context.Background(), streamType, reference, parameters)
+}
+
+// Same as [Client.ByRefNr] but the request is bound to the given context.
+func (o Client) ByRefNrCtx(ctx context.Context, streamType enum.StreamType, reference string, parameters params.QueryParameters) (*Response[MediaResultItem], error) {
+	return CallCtx(ctx, o, "get", streamType, "byrefnr", []string{reference}, parameters, 0, Response[MediaResultItem]{})
 }
 
 // Return a item of a given streamtype by it's slug.
-func (o Client) BySlug(streamType enum.StreamType, slug string, parameters params.QueryParameters) (*Response[any], error) {
-	return Call(o, "get", streamType, "byslug", []string{slug}, parameters, 1, Response[any]{})
+func (o Client) BySlug(streamType enum.StreamType, slug string, parameters params.QueryParameters) (*Response[MediaResultItem], error) {
+	return o.BySlugCtx(context.Background(), streamType, slug, parameters)
+}
+
+// Same as [Client.BySlug] but the request is bound to the given context.
+func (o Client) BySlugCtx(ctx context.Context, streamType enum.StreamType, slug string, parameters params.QueryParameters) (*Response[MediaResultItem], error) {
+	return CallCtx(ctx, o, "get", streamType, "byslug", []string{slug}, parameters, 0, Response[MediaResultItem]{})
 }
 
 // Return a item of a given streamtype by it's remote reference number.
 // This Call queries for an Item, that is (possibly) not hosted by nexxOMNIA. The API will
 // call the given Remote Provider for Media Details and implicitly create the Item for
 // future References within nexxOMNIA.
-func (o Client) ByRemoteRef(streamType enum.StreamType, reference string, parameters params.QueryParameters) (*Response[any], error) {
-	return Call(o, "get", streamType, "byremotereference", []string{reference}, parameters, 1, Response[any]{})
+func (o Client) ByRemoteRef(streamType enum.StreamType, reference string, parameters params.QueryParameters) (*Response[MediaResultItem], error) {
+	return o.ByRemoteRefCtx(context.Background(), streamType, reference, parameters)
+}
+
+// Same as [Client.ByRemoteRef] but the request is bound to the given context.
+func (o Client) ByRemoteRefCtx(ctx context.Context, streamType enum.StreamType, reference string, parameters params.QueryParameters) (*Response[MediaResultItem], error) {
+	return CallCtx(ctx, o, "get", streamType, "byremotereference", []string{reference}, parameters, 0, Response[MediaResultItem]{})
 }
 
 // Return a item of a given streamtype by it's code name. Only available for container
-// streamtypes.
-func (o Client) ByCodeName(streamType enum.StreamType, codename string, parameters params.QueryParameters) (*Response[any], error) {
-	return Call(o, "get", streamType, "bycodename", []string{codename}, parameters, 1, Response[any]{})
+// streamtypes (see [enum.StreamType.IsContainer]), an error wrapping [ErrValidation]
+// is returned for all other streamtypes. The result contains the child media of
+// the container.
+func (o Client) ByCodeName(streamType enum.StreamType, codename string, parameters params.QueryParameters) (*Response[ContainerResultItem], error) {
+	return o.ByCodeNameCtx(context.Background(), streamType, codename, parameters)
+}
+
+// Same as [Client.ByCodeName] but the request is bound to the given context.
+func (o Client) ByCodeNameCtx(ctx context.Context, streamType enum.StreamType, codename string, parameters params.QueryParameters) (*Response[ContainerResultItem], error) {
+	if !streamType.IsContainer() {
+		return nil, fmt.Errorf("%w, ByCodeName is only available for container streamtypes, %s given", ErrValidation, streamType)
+	}
+	return CallCtx(ctx, o, "get", streamType, "bycodename", []string{codename}, parameters, 0, Response[ContainerResultItem]{})
 }
 
 // Returns all media items of a given streamtype. Please note that it's not possible
@@ -190,58 +323,93 @@ func (o Client) ByCodeName(streamType enum.StreamType, codename string, paramete
 // 100 items of a given streamtype you'll should use the [Client.AllPaged] method
 // in order to get all items.
 func (o Client) All(streamType enum.StreamType, parameters params.QueryParameters) (*Response[MediaResult], error) {
-	return Call(o, "get", streamType, "all", nil, parameters, 1, Response[MediaResult]{})
+	return o.AllCtx(context.Background(), streamType, parameters)
+}
+
+// Same as [Client.All] but the request is bound to the given context.
+func (o Client) AllCtx(ctx context.Context, streamType enum.StreamType, parameters params.QueryParameters) (*Response[MediaResult], error) {
+	return CallCtx(ctx, o, "get", streamType, "all", nil, parameters, 0, Response[MediaResult]{})
 }
 
 // Joins results of multiple pages if there are more than 100 items and
-// the API starts to use paging.
+// the API starts to use paging. The offset of each page is computed from the
+// paging information of the previous response and items which appear on more than
+// one page (because items were added while paging) are only returned once. If the
+// total number of items changes while paging, the collected items are returned
+// together with a [*ResultCountChangedError]. Use [WithPagingRestarts] to start
+// over in this case.
 func (o Client) AllPaged(streamType enum.StreamType, parameters params.QueryParameters) (*Response[MediaResult], error) {
-	rqs, err := Call(o, "get", streamType, "all", nil, parameters, 1, Response[MediaResult]{})
-	if err != nil {
-		return nil, err
-	}
-	if rqs.Paging.ResultCount <= 100 {
-		return rqs, nil
-	}
-	for i := 100; i < rqs.Paging.ResultCount; i += 100 {
-		tmp, err := Call(o, "get", streamType, "all", nil, parameters, i, Response[MediaResult]{})
-		if err != nil {
-			return nil, err
-		}
-		rqs.Result = append(rqs.Result, tmp.Result...)
-	}
-	return rqs, nil
+	return o.AllPagedCtx(context.Background(), streamType, parameters)
+}
+
+// Same as [Client.AllPaged] but all page requests are bound to the given context.
+// The paging stops as soon as the context is canceled or its deadline is exceeded.
+func (o Client) AllPagedCtx(ctx context.Context, streamType enum.StreamType, parameters params.QueryParameters) (*Response[MediaResult], error) {
+	return o.allPaged(ctx, streamType, parameters, 0)
 }
 
 // Returns all items, sorted by Creation Date (ignores the "order" Parameters).
-func (o Client) Latest(streamType enum.StreamType, parameters params.QueryParameters) (*Response[any], error) {
-	return Call(o, "get", streamType, "latest", nil, parameters, 1, Response[any]{})
+func (o Client) Latest(streamType enum.StreamType, parameters params.QueryParameters) (*Response[MediaResult], error) {
+	return o.LatestCtx(context.Background(), streamType, parameters)
+}
+
+// Same as [Client.Latest] but the request is bound to the given context.
+func (o Client) LatestCtx(ctx context.Context, streamType enum.StreamType, parameters params.QueryParameters) (*Response[MediaResult], error) {
+	return CallCtx(ctx, o, "get", streamType, "latest", nil, parameters, 0, Response[MediaResult]{})
 }
 
 // Returns all picked media items of a given streamtype. Ignores the order parameter.
-func (o Client) Picked(streamType enum.StreamType, parameters params.QueryParameters) (*Response[any], error) {
-	return Call(o, "get", streamType, "picked", nil, parameters, 1, Response[any]{})
+func (o Client) Picked(streamType enum.StreamType, parameters params.QueryParameters) (*Response[MediaResult], error) {
+	return o.PickedCtx(context.Background(), streamType, parameters)
+}
+
+// Same as [Client.Picked] but the request is bound to the given context.
+func (o Client) PickedCtx(ctx context.Context, streamType enum.StreamType, parameters params.QueryParameters) (*Response[MediaResult], error) {
+	return CallCtx(ctx, o, "get", streamType, "picked", nil, parameters, 0, Response[MediaResult]{})
 }
 
 // Returns all evergreen media items of a given streamtype.
-func (o Client) Evergreens(streamType enum.StreamType, parameters params.QueryParameters) (*Response[any], error) {
-	return Call(o, "get", streamType, "evergreens", nil, parameters, 1, Response[any]{})
+func (o Client) Evergreens(streamType enum.StreamType, parameters params.QueryParameters) (*Response[MediaResult], error) {
+	return o.EvergreensCtx(context.Background(), streamType, parameters)
+}
+
+// Same as [Client.Evergreens] but the request is bound to the given context.
+func (o Client) EvergreensCtx(ctx context.Context, streamType enum.StreamType, parameters params.QueryParameters) (*Response[MediaResult], error) {
+	return CallCtx(ctx, o, "get", streamType, "evergreens", nil, parameters, 0, Response[MediaResult]{})
 }
 
 // Returns all Items, marked as "created for Kids". This is NOT connected to
 // any Age Restriction.
-func (o Client) ForKids(streamType enum.StreamType, parameters params.QueryParameters) (*Response[any], error) {
-	return Call(o, "get", streamType, "forkids", nil, parameters, 1, Response[any]{})
+func (o Client) ForKids(streamType enum.StreamType, parameters params.QueryParameters) (*Response[MediaResult], error) {
+	return o.ForKidsCtx(context.Background(), streamType, parameters)
+}
+
+// Same as [Client.ForKids] but the request is bound to the given context.
+func (o Client) ForKidsCtx(ctx context.Context, streamType enum.StreamType, parameters params.QueryParameters) (*Response[MediaResult], error) {
+	return CallCtx(ctx, o, "get", streamType, "forkids", nil, parameters, 0, Response[MediaResult]{})
 }
 
 // Performs a regular Query on all Items. The "order" Parameters are ignored,
-// if query-mode is set to "fulltext".
+// if query-mode is set to "fulltext". Use [params.ByQuery] to combine the query
+// options with basic and general parameters. Only the first page of the result is
+// returned, use [Client.IterateQuery] to page through all matches. Example, a
+// fulltext search for the first 10 audio items of channel 5:
+//
+//	client.ByQuery(enum.AudioStreamType, "interview", params.ByQuery{
+//		Basic:     params.Basic{Limit: 10},
+//		General:   params.General{Channel: 5},
+//		QueryMode: enum.FulltextQueryMode,
+//	})
+//
+// For fulltext queries the relevance of an item is available as
+// [MediaResultGeneral.QueryScore].
 func (o Client) ByQuery(streamType enum.StreamType, query string, parameters params.QueryParameters) (*Response[MediaResult], error) {
-	rsl, err := Call(o, "get", streamType, "byquery", []string{query}, parameters, 1, Response[MediaResult]{})
-	if err != nil {
-		return nil, err
-	}
-	return nil, fmt.Errorf("wrong type, should be MediaResponse but is %T", rsl)
+	return o.ByQueryCtx(context.Background(), streamType, query, parameters)
+}
+
+// Same as [Client.ByQuery] but the request is bound to the given context.
+func (o Client) ByQueryCtx(ctx context.Context, streamType enum.StreamType, query string, parameters params.QueryParameters) (*Response[MediaResult], error) {
+	return CallCtx(ctx, o, "get", streamType, "byquery", []string{url.PathEscape(query)}, parameters, 0, Response[MediaResult]{})
 }
 
 // Will update the general Metadata of a Media Item. Uses the Management API.
@@ -249,28 +417,39 @@ func (o Client) ByQuery(streamType enum.StreamType, query string, parameters par
 // item with the id 72:
 //
 //	client := omnia.NewClient("23", "Secret", "42")
-//	client.Update(enums.VideoStreamType, 72, params.Custom{
-//		"title": "My cool new title",
+//	client.Update(enums.VideoStreamType, 72, params.UpdateVideo{
+//		Title: "My cool new title",
 //	})
 //
-// As you see using a params.Custom map you can alter all the available metadata
-// fields. You can use the [Client.EditableAttributes] method to get a list of all fields
-// which are available and editable for an media item in omnia.
+// The typed parameters [params.UpdateVideo], [params.UpdateAudio] and
+// [params.UpdateShow] cover the documented editable attributes. Using a
+// params.Custom map you can alter all the available metadata fields. You can use
+// the [Client.EditableAttributes] method to get a list of all fields which are
+// available and editable for an media item in omnia. Use [WithUpdateValidation] or
+// [Client.ValidateUpdate] to check the parameters before sending them.
 //
 // [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#update
 func (o Client) Update(
 	streamType enum.StreamType,
 	id int,
-	parameters params.Custom,
+	parameters params.QueryParameters,
 ) (*Response[any], error) {
-	rsp, err := ManagementCall(o, "put", streamType, "update", []string{strconv.Itoa(id)}, parameters, Response[any]{})
-	if err != nil {
-		if rsp != nil {
-			return rsp, fmtOmniaErr(*rsp)
+	return o.UpdateCtx(context.Background(), streamType, id, parameters)
+}
+
+// Same as [Client.Update] but the request is bound to the given context.
+func (o Client) UpdateCtx(
+	ctx context.Context,
+	streamType enum.StreamType,
+	id int,
+	parameters params.QueryParameters,
+) (*Response[any], error) {
+	if o.validateUpdates {
+		if err := o.ValidateUpdate(ctx, streamType, parameters, false); err != nil {
+			return nil, err
 		}
-		return nil, err
 	}
-	return rsp, nil
+	return ManagementCallCtx(ctx, o, "put", streamType, "update", []string{strconv.Itoa(id)}, parameters, Response[any]{})
 }
 
 // Approves a media item of a given streamtype and item-id. Uses te Management API.
@@ -282,7 +461,17 @@ func (o Client) Approve(
 	id int,
 	parameters params.Approve,
 ) (*Response[any], error) {
-	return ManagementCall(o, "post", streamType, "approve", []string{strconv.Itoa(id)}, parameters, Response[any]{})
+	return o.ApproveCtx(context.Background(), streamType, id, parameters)
+}
+
+// Same as [Client.Approve] but the request is bound to the given context.
+func (o Client) ApproveCtx(
+	ctx context.Context,
+	streamType enum.StreamType,
+	id int,
+	parameters params.Approve,
+) (*Response[any], error) {
+	return ManagementCallCtx(ctx, o, "post", streamType, "approve", []string{strconv.Itoa(id)}, parameters, Response[any]{})
 }
 
 // Publish a media item of a given streamtype and item-id. Uses te Management API.
@@ -293,7 +482,16 @@ func (o Client) Publish(
 	streamType enum.StreamType,
 	id int,
 ) (*Response[any], error) {
-	return ManagementCall(o, "post", streamType, "publish", []string{strconv.Itoa(id)}, nil, Response[any]{})
+	return o.PublishCtx(context.Background(), streamType, id)
+}
+
+// Same as [Client.Publish] but the request is bound to the given context.
+func (o Client) PublishCtx(
+	ctx context.Context,
+	streamType enum.StreamType,
+	id int,
+) (*Response[any], error) {
+	return ManagementCallCtx(ctx, o, "post", streamType, "publish", []string{strconv.Itoa(id)}, nil, Response[any]{})
 }
 
 // Rejects a media item of a given streamtype and item-id. Uses te Management API.
@@ -305,7 +503,210 @@ func (o Client) Reject(
 	id int,
 	parameters params.Reject,
 ) (*Response[any], error) {
-	return ManagementCall(o, "post", streamType, "reject", []string{strconv.Itoa(id)}, parameters, Response[any]{})
+	return o.RejectCtx(context.Background(), streamType, id, parameters)
+}
+
+// Same as [Client.Reject] but the request is bound to the given context.
+func (o Client) RejectCtx(
+	ctx context.Context,
+	streamType enum.StreamType,
+	id int,
+	parameters params.Reject,
+) (*Response[any], error) {
+	return ManagementCallCtx(ctx, o, "post", streamType, "reject", []string{strconv.Itoa(id)}, parameters, Response[any]{})
+}
+
+// Removes the publishing of a media item of a given streamtype and item-id. Set
+// BlockFuturePublishing to prevent the item from being published again until
+// [Client.Unblock] is called. Uses the Management API. Documentation can be found
+// [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#unpublish
+func (o Client) Unpublish(
+	streamType enum.StreamType,
+	id int,
+	parameters params.Unpublish,
+) (*Response[ManagementResult], error) {
+	return o.UnpublishCtx(context.Background(), streamType, id, parameters)
+}
+
+// Same as [Client.Unpublish] but the request is bound to the given context.
+func (o Client) UnpublishCtx(
+	ctx context.Context,
+	streamType enum.StreamType,
+	id int,
+	parameters params.Unpublish,
+) (*Response[ManagementResult], error) {
+	return ManagementCallCtx(ctx, o, "post", streamType, "unpublish", []string{strconv.Itoa(id)}, parameters, Response[ManagementResult]{})
+}
+
+// Blocks a media item of a given streamtype and item-id. A blocked item is
+// unpublished and can't be published until [Client.Unblock] is called. Uses the
+// Management API. Documentation can be found [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#block
+func (o Client) Block(
+	streamType enum.StreamType,
+	id int,
+	parameters params.Block,
+) (*Response[ManagementResult], error) {
+	return o.BlockCtx(context.Background(), streamType, id, parameters)
+}
+
+// Same as [Client.Block] but the request is bound to the given context.
+func (o Client) BlockCtx(
+	ctx context.Context,
+	streamType enum.StreamType,
+	id int,
+	parameters params.Block,
+) (*Response[ManagementResult], error) {
+	return ManagementCallCtx(ctx, o, "post", streamType, "block", []string{strconv.Itoa(id)}, parameters, Response[ManagementResult]{})
+}
+
+// Lifts the block of a media item of a given streamtype and item-id set by
+// [Client.Block] or [Client.Unpublish]. Uses the Management API. Documentation can
+// be found [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#unblock
+func (o Client) Unblock(
+	streamType enum.StreamType,
+	id int,
+) (*Response[ManagementResult], error) {
+	return o.UnblockCtx(context.Background(), streamType, id)
+}
+
+// Same as [Client.Unblock] but the request is bound to the given context.
+func (o Client) UnblockCtx(
+	ctx context.Context,
+	streamType enum.StreamType,
+	id int,
+) (*Response[ManagementResult], error) {
+	return ManagementCallCtx(ctx, o, "post", streamType, "unblock", []string{strconv.Itoa(id)}, nil, Response[ManagementResult]{})
+}
+
+// Moves a media item of a given streamtype and item-id to the archive. Archived
+// items are no longer available in the Media API. Uses the Management API.
+// Documentation can be found [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#archive
+func (o Client) Archive(
+	streamType enum.StreamType,
+	id int,
+	parameters params.Archive,
+) (*Response[ManagementResult], error) {
+	return o.ArchiveCtx(context.Background(), streamType, id, parameters)
+}
+
+// Same as [Client.Archive] but the request is bound to the given context.
+func (o Client) ArchiveCtx(
+	ctx context.Context,
+	streamType enum.StreamType,
+	id int,
+	parameters params.Archive,
+) (*Response[ManagementResult], error) {
+	return ManagementCallCtx(ctx, o, "post", streamType, "archive", []string{strconv.Itoa(id)}, parameters, Response[ManagementResult]{})
+}
+
+// Restores an archived or (softly) deleted media item of a given streamtype and
+// item-id. Uses the Management API. Documentation can be found [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#restore
+func (o Client) Restore(
+	streamType enum.StreamType,
+	id int,
+) (*Response[ManagementResult], error) {
+	return o.RestoreCtx(context.Background(), streamType, id)
+}
+
+// Same as [Client.Restore] but the request is bound to the given context.
+func (o Client) RestoreCtx(
+	ctx context.Context,
+	streamType enum.StreamType,
+	id int,
+) (*Response[ManagementResult], error) {
+	return ManagementCallCtx(ctx, o, "post", streamType, "restore", []string{strconv.Itoa(id)}, nil, Response[ManagementResult]{})
+}
+
+// Deletes a media item of a given streamtype and item-id. By default the item is
+// moved to the trash and can be brought back using [Client.Restore]. Use
+// [enum.HardDelete] as mode to delete the item finally. Uses the Management API.
+// Documentation can be found [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#remove
+func (o Client) Delete(
+	streamType enum.StreamType,
+	id int,
+	parameters params.Delete,
+) (*Response[ManagementResult], error) {
+	return o.DeleteCtx(context.Background(), streamType, id, parameters)
+}
+
+// Same as [Client.Delete] but the request is bound to the given context.
+func (o Client) DeleteCtx(
+	ctx context.Context,
+	streamType enum.StreamType,
+	id int,
+	parameters params.Delete,
+) (*Response[ManagementResult], error) {
+	return ManagementCallCtx(ctx, o, "delete", streamType, "remove", []string{strconv.Itoa(id)}, parameters, Response[ManagementResult]{})
+}
+
+// Creates a new media item from a remote file. Only available for videos and audio.
+// Returns the IDs of the new item. Uses the Management API. Documentation can be
+// found [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#fromurl
+func (o Client) FromURL(
+	streamType enum.StreamType,
+	parameters params.FromURL,
+) (*Response[ManagementResult], error) {
+	return o.FromURLCtx(context.Background(), streamType, parameters)
+}
+
+// Same as [Client.FromURL] but the request is bound to the given context.
+func (o Client) FromURLCtx(
+	ctx context.Context,
+	streamType enum.StreamType,
+	parameters params.FromURL,
+) (*Response[ManagementResult], error) {
+	if streamType != enum.VideoStreamType && streamType != enum.AudioStreamType {
+		return nil, fmt.Errorf("%w, FromURL is only available for videos and audio, %s given", ErrValidation, streamType)
+	}
+	if err := parameters.Validate(); err != nil {
+		return nil, fmt.Errorf("%w, invalid parameters given for FromURL, %s", ErrValidation, err)
+	}
+	return ManagementCallCtx(ctx, o, "post", streamType, "fromurl", nil, parameters, Response[ManagementResult]{})
+}
+
+// Replaces the source file of an existing video or audio item with a remote file.
+// The item keeps its ID and all metadata. Covers and waveform are kept unless they
+// should be generated anew from the new file. Use [Client.ReplaceFileUpload] to
+// upload a local file instead. Uses the Management API. Documentation can be found
+// [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#replacefile
+func (o Client) ReplaceFile(
+	streamType enum.StreamType,
+	id int,
+	parameters params.ReplaceFile,
+) (*Response[ProcessingStatus], error) {
+	return o.ReplaceFileCtx(context.Background(), streamType, id, parameters)
+}
+
+// Same as [Client.ReplaceFile] but the request is bound to the given context.
+func (o Client) ReplaceFileCtx(
+	ctx context.Context,
+	streamType enum.StreamType,
+	id int,
+	parameters params.ReplaceFile,
+) (*Response[ProcessingStatus], error) {
+	if streamType != enum.VideoStreamType && streamType != enum.AudioStreamType {
+		return nil, fmt.Errorf("%w, ReplaceFile is only available for videos and audio, %s given", ErrValidation, streamType)
+	}
+	if err := parameters.Validate(); err != nil {
+		return nil, fmt.Errorf("%w, invalid parameters given for ReplaceFile, %s", ErrValidation, err)
+	}
+	return ManagementCallCtx(ctx, o, "post", streamType, "replacefile", []string{strconv.Itoa(id)}, parameters, Response[ProcessingStatus]{})
 }
 
 // Connect an media item to a show. Documentation can be found [here].
@@ -316,35 +717,65 @@ func (o Client) ConnectShow(
 	id int,
 	showId int,
 ) (*Response[any], error) {
-	return universalCall(o, "put", streamType, connectManagementApiType{}, "connectshow", []string{fmt.Sprint(id)}, fmt.Sprint(showId), nil, 1, Response[any]{})
+	return o.ConnectShowCtx(context.Background(), streamType, id, showId)
+}
+
+// Same as [Client.ConnectShow] but the request is bound to the given context.
+func (o Client) ConnectShowCtx(
+	ctx context.Context,
+	streamType enum.StreamType,
+	id int,
+	showId int,
+) (*Response[any], error) {
+	return universalCall(ctx, o, "put", streamType, itemTailManagementApiType{}, "connectshow", []string{fmt.Sprint(id)}, fmt.Sprint(showId), nil, 0, Response[any]{})
 }
 
 // Returns all available channels in omnia. Documentation can be found [here].
 //
 // [here]: https://api.nexx.cloud/v3.1/domain/channels
 func (o Client) Channels() (*Response[MediaResult], error) {
-	return DomainDataCall(o, "get", "channels", nil, nil, Response[MediaResult]{})
+	return o.ChannelsCtx(context.Background())
+}
+
+// Same as [Client.Channels] but the request is bound to the given context.
+func (o Client) ChannelsCtx(ctx context.Context) (*Response[MediaResult], error) {
+	return DomainDataCallCtx(ctx, o, "get", "channels", nil, nil, Response[MediaResult]{})
 }
 
 // Add a new channel. Documentation can be found [here].
 //
 // [here]: https://api.nexx.cloud/v3.1/manage/channels/add
 func (o Client) AddChannel(parameters params.Channel) (*Response[any], error) {
-	return ManagementCall(o, "post", "channels", "add", nil, parameters, Response[any]{})
+	return o.AddChannelCtx(context.Background(), parameters)
+}
+
+// Same as [Client.AddChannel] but the request is bound to the given context.
+func (o Client) AddChannelCtx(ctx context.Context, parameters params.Channel) (*Response[any], error) {
+	return ManagementCallCtx(ctx, o, "post", "channels", "add", nil, parameters, Response[any]{})
 }
 
 // Returns all available video categories in omnia. Documentation can be found [here].
 //
 // [here]: https://api.nexx.cloud/v3.1/domain/videocategories
 func (o Client) VideoCategories() (*Response[MediaResult], error) {
-	return DomainDataCall(o, "get", "videocategories", nil, nil, Response[MediaResult]{})
+	return o.VideoCategoriesCtx(context.Background())
+}
+
+// Same as [Client.VideoCategories] but the request is bound to the given context.
+func (o Client) VideoCategoriesCtx(ctx context.Context) (*Response[MediaResult], error) {
+	return DomainDataCallCtx(ctx, o, "get", "videocategories", nil, nil, Response[MediaResult]{})
 }
 
 // Returns all available audio categories in omnia. Documentation can be found [here].
 //
 // [here]: https://api.nexx.cloud/v3.1/domain/audiocategories
 func (o Client) AudioCategories() (*Response[MediaResult], error) {
-	return DomainDataCall(o, "get", "audiocategories", nil, nil, Response[MediaResult]{})
+	return o.AudioCategoriesCtx(context.Background())
+}
+
+// Same as [Client.AudioCategories] but the request is bound to the given context.
+func (o Client) AudioCategoriesCtx(ctx context.Context) (*Response[MediaResult], error) {
+	return DomainDataCallCtx(ctx, o, "get", "audiocategories", nil, nil, Response[MediaResult]{})
 }
 
 // Adds a new UploadLink. UploadsLinks are dynamic URLs, that allow external Users to
@@ -353,10 +784,15 @@ func (o Client) AudioCategories() (*Response[MediaResult], error) {
 //
 // [here]: https://api.docs.nexx.cloud/management-api/endpoints/domain-management#uploadlinks
 func (o Client) AddUploadLink(parameters params.UploadLink) (*Response[any], error) {
+	return o.AddUploadLinkCtx(context.Background(), parameters)
+}
+
+// Same as [Client.AddUploadLink] but the request is bound to the given context.
+func (o Client) AddUploadLinkCtx(ctx context.Context, parameters params.UploadLink) (*Response[any], error) {
 	if err := parameters.Validate(); err != nil {
-		return nil, fmt.Errorf("invalid parameters given for AddUploadLink, %s", err)
+		return nil, fmt.Errorf("%w, invalid parameters given for AddUploadLink, %s", ErrValidation, err)
 	}
-	return universalCall(o, http.MethodPost, enum.VideoStreamType, uploadLinkManagementApiType{}, "add", nil, "", parameters, 1, Response[any]{})
+	return universalCall(ctx, o, http.MethodPost, enum.VideoStreamType, uploadLinkManagementApiType{}, "add", nil, "", parameters, 0, Response[any]{})
 }
 
 // Lists all editable attributes for a given stream type. Documentation can be found [here].
@@ -367,7 +803,12 @@ func (o Client) AddUploadLink(parameters params.UploadLink) (*Response[any], err
 //
 // [here]: https://api.nexx.cloud/v3.1/system/editablerestrictionsfor/:streamtype
 func (o Client) EditableAttributes(streamType enum.StreamType) (*Response[EditableAttributesResponse], error) {
-	rsl, err := SystemCall(o, "get", "editableattributesfor", []string{string(streamType)}, Response[EditableAttributesResponse]{})
+	return o.EditableAttributesCtx(context.Background(), streamType)
+}
+
+// Same as [Client.EditableAttributes] but the request is bound to the given context.
+func (o Client) EditableAttributesCtx(ctx context.Context, streamType enum.StreamType) (*Response[EditableAttributesResponse], error) {
+	rsl, err := SystemCallCtx(ctx, o, "get", "editableattributesfor", []string{string(streamType)}, Response[EditableAttributesResponse]{})
 	if err != nil {
 		return nil, err
 	}
@@ -379,10 +820,20 @@ func (o Client) EditableAttributes(streamType enum.StreamType) (*Response[Editab
 //
 // [here]: https://api.nexx.cloud/v3.1/system/youtubecategories
 func (o Client) YouTubeCategories() (*Response[YouTubeCategories], error) {
-	return SystemCall(o, "get", "youtubecategories", nil, Response[YouTubeCategories]{})
+	return o.YouTubeCategoriesCtx(context.Background())
+}
+
+// Same as [Client.YouTubeCategories] but the request is bound to the given context.
+func (o Client) YouTubeCategoriesCtx(ctx context.Context) (*Response[YouTubeCategories], error) {
+	return SystemCallCtx(ctx, o, "get", "youtubecategories", nil, Response[YouTubeCategories]{})
 }
 
 // Generic call to the Omnia Media API. Won't work with the management API's.
+//
+// The pagingStart is the zero-based offset of the first item to return, 0 keeps the
+// start of the parameters (if any). A pagingStart of 1 is treated as 0 as it used to
+// be the value for the first page. This is deprecated, pass 0 for the first page and
+// use params.Basic{Start: 1} in order to skip the first item.
 func Call[T any](
 	o Client,
 	method string,
@@ -393,7 +844,38 @@ func Call[T any](
 	pagingStart int,
 	response Response[T],
 ) (*Response[T], error) {
-	return universalCall(o, method, streamType, mediaApiType{}, operation, args, "", parameters, pagingStart, response)
+	return CallCtx(context.Background(), o, method, streamType, operation, args, parameters, pagingStart, response)
+}
+
+// Same as [Call] but the request is bound to the given context.
+func CallCtx[T any](
+	ctx context.Context,
+	o Client,
+	method string,
+	streamType enum.StreamType,
+	operation string,
+	args []string,
+	parameters params.QueryParameters,
+	pagingStart int,
+	response Response[T],
+) (*Response[T], error) {
+	pagingStart, err := checkPagingStart(pagingStart)
+	if err != nil {
+		return nil, err
+	}
+	return universalCall(ctx, o, method, streamType, mediaApiType{}, operation, args, "", parameters, pagingStart, response)
+}
+
+// Returns the offset for the pagingStart of [Call]. The deprecated 1 for the first
+// page is mapped to 0, negative values are rejected.
+func checkPagingStart(pagingStart int) (int, error) {
+	if pagingStart < 0 {
+		return 0, fmt.Errorf("%w, pagingStart has to be a zero-based offset, %d given", ErrValidation, pagingStart)
+	}
+	if pagingStart == 1 {
+		return 0, nil
+	}
+	return pagingStart, nil
 }
 
 // Generic call to omnia's domain data API.
@@ -405,7 +887,20 @@ func DomainDataCall[T any](
 	parameters params.QueryParameters,
 	response Response[T],
 ) (*Response[T], error) {
-	return universalCall(o, method, enum.AllStreamType, domainDataApiType{}, operation, args, "", parameters, 1, response)
+	return DomainDataCallCtx(context.Background(), o, method, operation, args, parameters, response)
+}
+
+// Same as [DomainDataCall] but the request is bound to the given context.
+func DomainDataCallCtx[T any](
+	ctx context.Context,
+	o Client,
+	method string,
+	operation string,
+	args []string,
+	parameters params.QueryParameters,
+	response Response[T],
+) (*Response[T], error) {
+	return universalCall(ctx, o, method, enum.AllStreamType, domainDataApiType{}, operation, args, "", parameters, 0, response)
 }
 
 // Generic call to the Omnia management API.
@@ -418,7 +913,21 @@ func ManagementCall[T any](
 	parameters params.QueryParameters,
 	response Response[T],
 ) (*Response[T], error) {
-	return universalCall(o, method, streamType, managementApiType{}, operation, args, "", parameters, 1, response)
+	return ManagementCallCtx(context.Background(), o, method, streamType, operation, args, parameters, response)
+}
+
+// Same as [ManagementCall] but the request is bound to the given context.
+func ManagementCallCtx[T any](
+	ctx context.Context,
+	o Client,
+	method string,
+	streamType enum.StreamType,
+	operation string,
+	args []string,
+	parameters params.QueryParameters,
+	response Response[T],
+) (*Response[T], error) {
+	return universalCall(ctx, o, method, streamType, managementApiType{}, operation, args, "", parameters, 0, response)
 }
 
 // Generic call to the Omnia system API
@@ -429,10 +938,25 @@ func SystemCall[T any](
 	args []string,
 	response Response[T],
 ) (*Response[T], error) {
-	return universalCall(o, method, enum.VideoStreamType, systemApiType{}, operation, args, "", nil, 1, response)
+	return SystemCallCtx(context.Background(), o, method, operation, args, response)
 }
 
+// Same as [SystemCall] but the request is bound to the given context.
+func SystemCallCtx[T any](
+	ctx context.Context,
+	o Client,
+	method string,
+	operation string,
+	args []string,
+	response Response[T],
+) (*Response[T], error) {
+	return universalCall(ctx, o, method, enum.VideoStreamType, systemApiType{}, operation, args, "", nil, 0, response)
+}
+
+// Performs the actual HTTP request against the API. The request is canceled as soon
+// as the given context is done.
 func universalCall[T any](
+	ctx context.Context,
 	o Client,
 	method string,
 	streamType enum.StreamType,
@@ -444,86 +968,186 @@ func universalCall[T any](
 	pagingStart int,
 	response Response[T],
 ) (*Response[T], error) {
+	return universalBodyCall(ctx, o, method, streamType, aType, operation, args, tail, parameters, pagingStart, nil, response)
+}
+
+// Same as [universalCall] but sends the given body with the request.
+func universalBodyCall[T any](
+	ctx context.Context,
+	o Client,
+	method string,
+	streamType enum.StreamType,
+	aType apiType,
+	operation string,
+	args []string,
+	tail string,
+	parameters params.QueryParameters,
+	pagingStart int,
+	body []byte,
+	response Response[T],
+) (rsl *Response[T], err error) {
 	method = strings.ToUpper(method)
+	attributes := []Attribute{
+		{Key: "omnia.domain_id", Value: o.DomainId},
+		{Key: "omnia.stream_type", Value: string(streamType)},
+		{Key: "omnia.operation", Value: operation},
+		{Key: "omnia.api_class", Value: string(aType.Class())},
+		{Key: "http.method", Value: method},
+	}
+	if pagingStart > 0 || isListOperation(operation) {
+		attributes = append(attributes, Attribute{Key: "omnia.paging.start", Value: pagingStart})
+	}
+	ctx, span := o.tracing().Start(ctx, "gomnia."+operation, attributes...)
+	defer func() {
+		if rsl != nil {
+			span.SetAttributes(Attribute{Key: "omnia.status", Value: rsl.Metadata.Status})
+			if rsl.Metadata.Notice != nil {
+				span.SetAttributes(Attribute{Key: "omnia.notice", Value: *rsl.Metadata.Notice})
+			}
+		}
+		endSpan(span, err)
+	}()
+
 	argsParts := ""
 	if len(args) > 0 {
 		argsParts = strings.Join(args, "/")
 		argsParts = fmt.Sprintf("/%s", argsParts)
 	}
-	reqUrl := aType.UrlBuilder(o.DomainId, streamType, operation, argsParts, tail)
-	header := newOmniaHeader(operation, o.DomainId, o.ApiSecret, o.SessionId)
+	reqUrl := aType.UrlBuilder(o.apiBaseUrl(), o.DomainId, streamType, operation, argsParts, tail)
+	header := newOmniaHeader(o.log(), operation, o.DomainId, o.ApiSecret, o.SessionId)
 
-	limitParam, err := params.Basic{
-		Limit: 100,
-		Start: pagingStart,
-	}.UrlEncode()
+	// The caller's limit and start take precedence over the default page size. The
+	// offset of a following page is controlled by the paging. The parameters of the
+	// caller are merged on their own first, so defaults and overrides given by the
+	// caller don't conflict with the ones of the library.
+	caller, err := params.Merge(parameters)
 	if err != nil {
 		return nil, err
 	}
-	var paramUrl string
-	if parameters != nil {
-		var err error
-		paramUrl, err = parameters.UrlEncode()
-		if err != nil {
-			return nil, err
-		}
-		paramUrl = fmt.Sprintf("%s&%s", paramUrl, limitParam)
-	} else {
-		paramUrl = limitParam
+	merge := []params.QueryParameters{params.Default(params.Basic{Limit: pageSize}), caller}
+	if pagingStart > 0 {
+		merge = append(merge, params.Override(params.Basic{Start: pagingStart}))
+	}
+	values, err := params.Merge(merge...)
+	if err != nil {
+		return nil, err
+	}
+	paramUrl, err := values.UrlEncode()
+	if err != nil {
+		return nil, err
 	}
 	o.debugLog(method, reqUrl, header, paramUrl)
 
+	endpoint := reqUrl
 	reqUrl = fmt.Sprintf("%s?%s", reqUrl, paramUrl)
 
-	req, err := http.NewRequest(method, reqUrl, nil)
+	req := &Request{
+		Method:     method,
+		URL:        reqUrl,
+		Endpoint:   endpoint,
+		Header:     http.Header{},
+		StreamType: streamType,
+		Operation:  operation,
+		Class:      aType.Class(),
+		Args:       args,
+		Params:     parameters,
+		Body:       body,
+	}
+	req.Header.Set(omniaHeaderXRequestCid, header.xRequestCid)
+	req.Header.Set(omniaHeaderXRequestToken, header.xRequestToken)
+	if o.userAgent != "" {
+		req.Header.Set("User-Agent", o.userAgent)
+	}
+	rsp, err := o.roundTrip()(ctx, req)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Add(omniaHeaderXRequestCid, header.xRequestCid)
-	req.Header.Add(omniaHeaderXRequestToken, header.xRequestToken)
-	client := http.Client{
-		Timeout: time.Second * 10,
+	span.SetAttributes(Attribute{Key: "http.status_code", Value: rsp.StatusCode})
+	logger := o.log()
+	if logger.Enabled(TraceLevel) {
+		logger.Trace("Response Body", Fields{"body": string(rsp.Body)})
 	}
-	rsp, err := client.Do(req)
+	err = json.Unmarshal(rsp.Body, &response)
 	if err != nil {
+		if !rsp.successful() {
+			return nil, newApiError(rsp.StatusCode, ResponseMetadata{}, method, endpoint, operation, streamType)
+		}
 		return nil, err
 	}
-	defer rsp.Body.Close()
-	body, err := io.ReadAll(rsp.Body)
+	if logger.Enabled(DebugLevel) {
+		if fields, err := response.Metadata.toMap(); err == nil {
+			logger.Debug("Response Metadata", fields)
+		}
+		if response.Paging != nil {
+			if fields, err := response.Paging.toMap(); err == nil {
+				logger.Debug("Response Paging", fields)
+			}
+		}
+	}
+	if response.Metadata.Status != 200 && response.Metadata.Status != 201 {
+		return &response, newApiError(rsp.StatusCode, response.Metadata, method, endpoint, operation, streamType)
+	}
+	if logger.Enabled(TraceLevel) {
+		if result, err := json.Marshal(response.Result); err == nil {
+			logger.Trace("Response Result", Fields{"result": string(result)})
+		}
+	}
+	return &response, nil
+}
+
+// Performs a single HTTP request and reads the complete response body. The timeout of
+// the client is applied to the request.
+func (o Client) sendOnce(ctx context.Context, call *Request) (*RawResponse, error) {
+	if timeout := o.requestTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	var reqBody io.Reader
+	if call.Body != nil {
+		reqBody = bytes.NewReader(call.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, call.Method, call.URL, reqBody)
 	if err != nil {
 		return nil, err
 	}
-	logrus.Trace(string(body))
-	err = json.Unmarshal(body, &response)
+	req.Header = call.Header.Clone()
+	if call.Body != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	rsp, err := o.client().Do(req)
 	if err != nil {
 		return nil, err
 	}
-	logrus.WithFields(response.Metadata.toMap()).Debug("Response Metadata")
-	if response.Paging != nil {
-		logrus.WithFields(response.Paging.toMap()).Debug("Response Paging")
-	}
-	if response.Metadata.Status != 200 && response.Metadata.Status != 201 {
-		return &response, fmt.Errorf("call failed on server side with status code %d, %s", response.Metadata.Status, *response.Metadata.ErrorHint)
+	defer rsp.Body.Close()
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
 	}
-	logrus.Trace(response.Result)
-	return &response, nil
+	return &RawResponse{
+		StatusCode: rsp.StatusCode,
+		Header:     rsp.Header,
+		Body:       body,
+	}, nil
 }
 
 // Logs parameters of API call.
 func (o Client) debugLog(method string, url string, header omniaHeader, parameters string) {
+	logger := o.log()
+	if !logger.Enabled(DebugLevel) {
+		return
+	}
 	var paramStr string
 	if parameters != "" {
 		paramStr = fmt.Sprintf("%+v", parameters)
 	}
-	logrus.WithFields(logrus.Fields{
+	logger.Debug("send request to Omnia", Fields{
 		"method": method,
 		"url":    url,
-		"header": fmt.Sprintf("%+v", header),
+		"header": map[string]string{
+			omniaHeaderXRequestCid:   header.xRequestCid,
+			omniaHeaderXRequestToken: header.xRequestToken,
+		},
 		"params": paramStr,
-	}).Debug("send request to Omnia")
-}
-
-// Formats an error message from an Omnia response.
-func fmtOmniaErr[T any](rsp Response[T]) error {
-	return fmt.Errorf("call to omnia failed with %d, %s", rsp.Metadata.Status, *rsp.Metadata.ErrorHint)
+	})
 }
@@ -0,0 +1,82 @@
+package gomnia_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	omnia "github.com/alex-berlin-tv/gomnia"
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/alex-berlin-tv/gomnia/params"
+)
+
+// Returns a client whose requests are answered with an empty listing and a function
+// returning the query of the last request.
+func newQueryRecorder(t *testing.T) (omnia.Client, func() url.Values) {
+	t.Helper()
+	var mutex sync.Mutex
+	var query url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		query = r.URL.Query()
+		mutex.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"metadata":{"status":200},"result":[]}`)
+	}))
+	t.Cleanup(srv.Close)
+	client := omnia.NewClient("1", "secret", "session", omnia.WithBaseURL(srv.URL))
+	return client, func() url.Values {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return query
+	}
+}
+
+func TestCallParameterPrecedence(t *testing.T) {
+	client, query := newQueryRecorder(t)
+	tests := []struct {
+		name        string
+		parameters  params.QueryParameters
+		pagingStart int
+		limit       string
+		start       string
+	}{
+		{"default page size", nil, 0, "100", ""},
+		{"caller's limit and start", params.Basic{Limit: 10, Start: 20}, 0, "10", "20"},
+		{"caller's start with default page size", params.Basic{Start: 20}, 0, "100", "20"},
+		{"paging start wins over the caller's start", params.Basic{Limit: 10, Start: 20}, 30, "10", "30"},
+		{"caller's default wins over the page size", params.Default(params.Basic{Limit: 50}), 0, "50", ""},
+		{"paging start wins over the caller's override", params.Override(params.Basic{Start: 20}), 30, "100", "30"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := omnia.Call(client, "get", enum.AudioStreamType, "all", nil, tt.parameters, tt.pagingStart, omnia.Response[omnia.MediaResult]{}); err != nil {
+				t.Fatal(err)
+			}
+			got := query()
+			if got.Get("limit") != tt.limit || got.Get("start") != tt.start {
+				t.Fatalf("sent limit %q and start %q, want %q and %q", got.Get("limit"), got.Get("start"), tt.limit, tt.start)
+			}
+			if len(got["limit"]) > 1 || len(got["start"]) > 1 {
+				t.Fatalf("parameters were sent more than once: %v", got)
+			}
+		})
+	}
+}
+
+func TestCallMergedParameters(t *testing.T) {
+	client, query := newQueryRecorder(t)
+	merged, err := params.Merge(params.Basic{Limit: 10}, params.Custom{"channel": "5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.All(enum.AudioStreamType, merged); err != nil {
+		t.Fatal(err)
+	}
+	if got := query(); got.Get("limit") != "10" || got.Get("channel") != "5" {
+		t.Fatalf("unexpected query %v", got)
+	}
+}
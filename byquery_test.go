@@ -0,0 +1,155 @@
+package gomnia_test
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/alex-berlin-tv/gomnia/gomniatest/fakeserver"
+	"github.com/alex-berlin-tv/gomnia/params"
+)
+
+// Returns items with the given titles.
+func titled(titles ...string) []fakeserver.Item {
+	rsl := make([]fakeserver.Item, len(titles))
+	for i, title := range titles {
+		rsl[i].General.Title = title
+	}
+	return rsl
+}
+
+func newSearchServer(t *testing.T, items ...fakeserver.Item) *fakeserver.Server {
+	t.Helper()
+	srv := fakeserver.New(fakeserver.WithItems(enum.AudioStreamType, items...))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// Returns the query of the last byquery request received by the server.
+func lastQuery(t *testing.T, srv *fakeserver.Server) url.Values {
+	t.Helper()
+	requests := srv.Requests()
+	for i := len(requests) - 1; i >= 0; i-- {
+		if requests[i].Operation == "byquery" {
+			return requests[i].Query
+		}
+	}
+	t.Fatal("no byquery request received")
+	return nil
+}
+
+func TestByQuery(t *testing.T) {
+	srv := newSearchServer(t, titled("Interview with Anna", "Concert", "Interview after the concert")...)
+	client := srv.Client()
+
+	rsl, err := client.ByQuery(enum.AudioStreamType, "interview", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(rsl.Result), []int{1, 3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got items %v, want %v", got, want)
+	}
+	if rsl.Paging == nil || rsl.Paging.ResultCount != 2 {
+		t.Fatalf("unexpected paging %+v", rsl.Paging)
+	}
+	for _, item := range rsl.Result {
+		if item.General.QueryScore != nil {
+			t.Fatalf("item %d has a query score outside of the fulltext mode", item.General.Id)
+		}
+	}
+
+	rsl, err = client.ByQuery(enum.AudioStreamType, "concert interview", params.ByQuery{QueryMode: enum.FulltextQueryMode})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(rsl.Result), []int{3, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got items %v ordered by score, want %v", got, want)
+	}
+	for i, want := range []float64{2, 1, 1} {
+		score := rsl.Result[i].General.QueryScore
+		if score == nil || *score != want {
+			t.Fatalf("item %d has the query score %v, want %v", rsl.Result[i].General.Id, score, want)
+		}
+	}
+}
+
+func TestByQueryParameters(t *testing.T) {
+	srv := newSearchServer(t, titled("Interview")...)
+	client := srv.Client()
+
+	_, err := client.ByQuery(enum.AudioStreamType, "interview", params.ByQuery{
+		Basic:                   params.Basic{Limit: 10},
+		General:                 params.General{Channel: 5},
+		QueryMode:               enum.ClassicWithOrQueryMode,
+		QueryFields:             []string{"title", "description"},
+		IncludeSubstringMatches: true,
+		SkipReporting:           true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := url.Values{
+		"limit":                   {"10"},
+		"channel":                 {"5"},
+		"queryMode":               {string(enum.ClassicWithOrQueryMode)},
+		"queryFields":             {"title,description"},
+		"includeSubstringMatches": {"1"},
+		"skipReporting":           {"1"},
+	}
+	got := lastQuery(t, srv)
+	for key, value := range want {
+		if !reflect.DeepEqual(got[key], value) {
+			t.Errorf("sent %s %q, want %q", key, got[key], value)
+		}
+	}
+
+	if _, err := client.ByQuery(enum.AudioStreamType, "interview", params.ByQuery{}); err != nil {
+		t.Fatal(err)
+	}
+	got = lastQuery(t, srv)
+	for _, key := range []string{"queryFields", "includeSubstringMatches", "skipReporting"} {
+		if _, ok := got[key]; ok {
+			t.Errorf("sent the unset %s %q", key, got[key])
+		}
+	}
+}
+
+func TestIterateQuery(t *testing.T) {
+	var items []fakeserver.Item
+	for i := 1; i <= 250; i++ {
+		items = append(items, titled(fmt.Sprintf("Episode %d", i), fmt.Sprintf("Trailer %d", i))...)
+	}
+	srv := newSearchServer(t, items...)
+	client := srv.Client()
+
+	it := client.IterateQuery(context.Background(), enum.AudioStreamType, "episode", nil)
+	var got []int
+	for it.Next() {
+		got = append(got, it.Item().General.Id)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 250 {
+		t.Fatalf("got %d items, want 250", len(got))
+	}
+	for i, id := range got {
+		// The episodes have the odd IDs.
+		if id != 2*i+1 {
+			t.Fatalf("got item %d at %d, want %d", id, i, 2*i+1)
+		}
+	}
+
+	var starts []string
+	for _, request := range srv.Requests() {
+		if request.Operation == "byquery" {
+			starts = append(starts, request.Query.Get("start"))
+		}
+	}
+	if want := []string{"", "100", "200"}; !reflect.DeepEqual(starts, want) {
+		t.Fatalf("requested offsets %q, want %q", starts, want)
+	}
+}
@@ -0,0 +1,148 @@
+package gomnia
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/alex-berlin-tv/gomnia/params"
+)
+
+// Returns an error if the cover variant is unknown.
+func checkCoverType(method string, variant enum.CoverType) error {
+	if _, err := enum.EnumByValue[enum.CoverType](enum.DefaultCoverType, variant); err != nil {
+		return fmt.Errorf("%w, %s got an invalid cover variant %q", ErrValidation, method, variant)
+	}
+	return nil
+}
+
+// Sets a cover variant of a media item of a given streamtype and item-id to a
+// remote image. An existing cover of the variant is replaced. Uses the Management
+// API. Documentation can be found [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#covers
+func (o Client) SetCoverFromURL(
+	streamType enum.StreamType,
+	id int,
+	variant enum.CoverType,
+	parameters params.CoverFromURL,
+) (*Response[CoverResult], error) {
+	return o.SetCoverFromURLCtx(context.Background(), streamType, id, variant, parameters)
+}
+
+// Same as [Client.SetCoverFromURL] but the request is bound to the given context.
+func (o Client) SetCoverFromURLCtx(
+	ctx context.Context,
+	streamType enum.StreamType,
+	id int,
+	variant enum.CoverType,
+	parameters params.CoverFromURL,
+) (*Response[CoverResult], error) {
+	if err := checkCoverType("SetCoverFromURL", variant); err != nil {
+		return nil, err
+	}
+	if err := parameters.Validate(); err != nil {
+		return nil, fmt.Errorf("%w, invalid parameters given for SetCoverFromURL, %s", ErrValidation, err)
+	}
+	return universalCall(ctx, o, "post", streamType, itemTailManagementApiType{}, "addcover", []string{strconv.Itoa(id)}, string(variant), parameters, 0, Response[CoverResult]{})
+}
+
+// Uploads an image as cover variant of a media item of a given streamtype and
+// item-id. An existing cover of the variant is replaced. The image is read
+// completely before it's sent. Uses the Management API. Documentation can be found
+// [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#covers
+func (o Client) UploadCover(
+	streamType enum.StreamType,
+	id int,
+	variant enum.CoverType,
+	image io.Reader,
+	parameters params.CoverUpload,
+) (*Response[CoverResult], error) {
+	return o.UploadCoverCtx(context.Background(), streamType, id, variant, image, parameters)
+}
+
+// Same as [Client.UploadCover] but the request is bound to the given context.
+func (o Client) UploadCoverCtx(
+	ctx context.Context,
+	streamType enum.StreamType,
+	id int,
+	variant enum.CoverType,
+	image io.Reader,
+	parameters params.CoverUpload,
+) (*Response[CoverResult], error) {
+	if err := checkCoverType("UploadCover", variant); err != nil {
+		return nil, err
+	}
+	if err := parameters.Validate(); err != nil {
+		return nil, fmt.Errorf("%w, invalid parameters given for UploadCover, %s", ErrValidation, err)
+	}
+	data, err := io.ReadAll(image)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read cover image, %w", err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w, empty image given for UploadCover", ErrValidation)
+	}
+	return universalBodyCall(ctx, o, "put", streamType, itemTailManagementApiType{}, "uploadcover", []string{strconv.Itoa(id)}, string(variant), parameters, 0, data, Response[CoverResult]{})
+}
+
+// Changes the description (alt text) and language of a cover variant of a media
+// item of a given streamtype and item-id. Uses the Management API. Documentation can
+// be found [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#covers
+func (o Client) SetCoverDescription(
+	streamType enum.StreamType,
+	id int,
+	variant enum.CoverType,
+	parameters params.CoverDescription,
+) (*Response[CoverResult], error) {
+	return o.SetCoverDescriptionCtx(context.Background(), streamType, id, variant, parameters)
+}
+
+// Same as [Client.SetCoverDescription] but the request is bound to the given
+// context.
+func (o Client) SetCoverDescriptionCtx(
+	ctx context.Context,
+	streamType enum.StreamType,
+	id int,
+	variant enum.CoverType,
+	parameters params.CoverDescription,
+) (*Response[CoverResult], error) {
+	if err := checkCoverType("SetCoverDescription", variant); err != nil {
+		return nil, err
+	}
+	if err := parameters.Validate(); err != nil {
+		return nil, fmt.Errorf("%w, invalid parameters given for SetCoverDescription, %s", ErrValidation, err)
+	}
+	return universalCall(ctx, o, "put", streamType, itemTailManagementApiType{}, "updatecover", []string{strconv.Itoa(id)}, string(variant), parameters, 0, Response[CoverResult]{})
+}
+
+// Removes a cover variant of a media item of a given streamtype and item-id. Uses the
+// Management API. Documentation can be found [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#covers
+func (o Client) RemoveCover(
+	streamType enum.StreamType,
+	id int,
+	variant enum.CoverType,
+) (*Response[ManagementResult], error) {
+	return o.RemoveCoverCtx(context.Background(), streamType, id, variant)
+}
+
+// Same as [Client.RemoveCover] but the request is bound to the given context.
+func (o Client) RemoveCoverCtx(
+	ctx context.Context,
+	streamType enum.StreamType,
+	id int,
+	variant enum.CoverType,
+) (*Response[ManagementResult], error) {
+	if err := checkCoverType("RemoveCover", variant); err != nil {
+		return nil, err
+	}
+	return universalCall(ctx, o, "delete", streamType, itemTailManagementApiType{}, "removecover", []string{strconv.Itoa(id)}, string(variant), nil, 0, Response[ManagementResult]{})
+}
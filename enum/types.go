@@ -1,5 +1,7 @@
 package enum
 
+import "encoding/json"
+
 // A boolean value is expressed as a 0 for `false` and 1 for `true`.
 // String is used as type as it's not possible to nil integer values
 // (which is needed in order to omit unset parameters as the query parameter).
@@ -187,6 +189,11 @@ const (
 	VideoStreamType = StreamType("videos")
 	AudioStreamType = StreamType("audio")
 	ShowStreamType  = StreamType("shows")
+	// Container streamtypes, grouping other media items.
+	PlaylistStreamType   = StreamType("playlists")
+	SetStreamType        = StreamType("sets")
+	CollectionStreamType = StreamType("collections")
+	AudioAlbumStreamType = StreamType("audioalbums")
 )
 
 // All instances of the StreamType
@@ -196,9 +203,22 @@ func (i StreamType) Instances() []StreamType {
 		VideoStreamType,
 		AudioStreamType,
 		ShowStreamType,
+		PlaylistStreamType,
+		SetStreamType,
+		CollectionStreamType,
+		AudioAlbumStreamType,
 	}
 }
 
+// Whether the streamtype is a container, grouping other media items.
+func (i StreamType) IsContainer() bool {
+	switch i {
+	case ShowStreamType, PlaylistStreamType, SetStreamType, CollectionStreamType, AudioAlbumStreamType:
+		return true
+	}
+	return false
+}
+
 func (i *StreamType) UnmarshalJSON(data []byte) (err error) {
 	value, err := EnumByByteValue[StreamType](VideoStreamType, data)
 	*(*StreamType)(i) = *value
@@ -424,3 +444,106 @@ func (i *ActionAfterRejection) UnmarshalJSON(data []byte) (err error) {
 	*(*ActionAfterRejection)(i) = *value
 	return err
 }
+
+// How an item is deleted.
+type DeleteMode string
+
+const (
+	// The item is moved to the trash and can be restored.
+	SoftDelete = DeleteMode("soft")
+	// The item and all its files are deleted finally.
+	HardDelete = DeleteMode("hard")
+)
+
+// All instances of the DeleteMode
+func (i DeleteMode) Instances() []DeleteMode {
+	return []DeleteMode{
+		SoftDelete,
+		HardDelete,
+	}
+}
+
+func (i *DeleteMode) UnmarshalJSON(data []byte) (err error) {
+	value, err := EnumByByteValue[DeleteMode](SoftDelete, data)
+	*(*DeleteMode)(i) = *value
+	return err
+}
+
+// Processing state of the source file of an item.
+type ProcessingState string
+
+const (
+	// The file waits in the queue of the domain.
+	QueuedProcessingState = ProcessingState("queued")
+	// The file is being fetched or transcoded.
+	RunningProcessingState = ProcessingState("processing")
+	// The file was processed and is in use.
+	FinishedProcessingState = ProcessingState("finished")
+	// The processing failed, the previous file is kept.
+	FailedProcessingState = ProcessingState("failed")
+)
+
+// All instances of the ProcessingState
+func (i ProcessingState) Instances() []ProcessingState {
+	return []ProcessingState{
+		QueuedProcessingState,
+		RunningProcessingState,
+		FinishedProcessingState,
+		FailedProcessingState,
+	}
+}
+
+// Unlike most other enums the state is a JSON string.
+func (i *ProcessingState) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	value, err := EnumByValue[ProcessingState](QueuedProcessingState, ProcessingState(raw))
+	if err != nil {
+		return err
+	}
+	*i = *value
+	return nil
+}
+
+// Variants of the cover of an item.
+type CoverType string
+
+const (
+	// The main cover, see Thumb in the image data.
+	DefaultCoverType = CoverType("cover")
+	// The cover used for teasers with an action, see ThumbAction.
+	ActionCoverType = CoverType("action")
+	// The wide banner, see ThumbBanner.
+	BannerCoverType = CoverType("banner")
+	// The square cover, see ThumbQuad.
+	QuadCoverType = CoverType("quad")
+	// The alternative cover for A/B tests, see ThumbAbt.
+	AbtCoverType = CoverType("abt")
+)
+
+// All instances of the CoverType
+func (i CoverType) Instances() []CoverType {
+	return []CoverType{
+		DefaultCoverType,
+		ActionCoverType,
+		BannerCoverType,
+		QuadCoverType,
+		AbtCoverType,
+	}
+}
+
+// Like [ProcessingState] the cover type is a JSON string.
+func (i *CoverType) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	value, err := EnumByValue[CoverType](DefaultCoverType, CoverType(raw))
+	if err != nil {
+		return err
+	}
+	*i = *value
+	return nil
+}
@@ -0,0 +1,104 @@
+package gomnia
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/alex-berlin-tv/gomnia/enum"
+)
+
+// Sentinel errors which can be used with [errors.Is] to check the cause of a failed
+// API call. They are matched by [APIError] based on the status of the response.
+var (
+	// The requested item or endpoint does not exist.
+	ErrNotFound = errors.New("not found")
+	// The credentials of the client are invalid or the session lacks the needed
+	// permissions.
+	ErrUnauthorized = errors.New("unauthorized")
+	// The request quota of the domain is exceeded.
+	ErrRateLimited = errors.New("rate limited")
+	// The request was rejected because of invalid parameters.
+	ErrValidation = errors.New("validation failed")
+)
+
+// APIError is returned by all calls to the API (Media, Management, Domain and System
+// alike) when omnia answers with a non-successful status. Use [errors.As] to access
+// the details or [errors.Is] with one of the sentinel errors (for example
+// [ErrNotFound]) to check for a certain kind of failure:
+//
+//	_, err := client.ById(enum.AudioStreamType, 72, nil)
+//	if errors.Is(err, omnia.ErrNotFound) {
+//		// Handle missing item.
+//	}
+type APIError struct {
+	// Status code of the HTTP response.
+	HttpStatus int
+	// Status as reported by omnia in [ResponseMetadata.Status]. Zero if the
+	// response didn't contain any metadata.
+	Status int
+	// Hint for the failure reason as given by [ResponseMetadata.ErrorHint].
+	ErrorHint string
+	// Notice on deprecated functionality as given by [ResponseMetadata.Notice].
+	Notice string
+	// HTTP method used for the call.
+	Method string
+	// The called URL without the query parameters.
+	Endpoint string
+	// The called operation (e.g. `byid` or `update`).
+	Operation string
+	// Streamtype of the call.
+	StreamType enum.StreamType
+}
+
+func newApiError(httpStatus int, metadata ResponseMetadata, method, endpoint, operation string, streamType enum.StreamType) *APIError {
+	return &APIError{
+		HttpStatus: httpStatus,
+		Status:     metadata.Status,
+		ErrorHint:  derefString(metadata.ErrorHint),
+		Notice:     derefString(metadata.Notice),
+		Method:     method,
+		Endpoint:   endpoint,
+		Operation:  operation,
+		StreamType: streamType,
+	}
+}
+
+func (e *APIError) Error() string {
+	rsl := fmt.Sprintf("call to %s %s failed on server side with status code %d", e.Method, e.Endpoint, e.StatusCode())
+	if e.ErrorHint != "" {
+		rsl = fmt.Sprintf("%s, %s", rsl, e.ErrorHint)
+	}
+	return rsl
+}
+
+// Returns the status of the failed call. The status given by omnia in the response
+// metadata takes precedence over the status of the HTTP response.
+func (e *APIError) StatusCode() int {
+	if e.Status != 0 {
+		return e.Status
+	}
+	return e.HttpStatus
+}
+
+// Reports whether the error matches one of the sentinel errors of this package.
+func (e *APIError) Is(target error) bool {
+	switch e.StatusCode() {
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return target == ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return target == ErrRateLimited
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return target == ErrValidation
+	}
+	return false
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,130 @@
+package fakeserver
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	omnia "github.com/alex-berlin-tv/gomnia"
+	"github.com/alex-berlin-tv/gomnia/enum"
+)
+
+// Returns the item and the cover variant addressed by a cover operation:
+// manage/{streamType}/{id}/{operation}/{variant}.
+func (s *Server) coverTarget(c *call) (*Item, enum.CoverType, error) {
+	item, err := s.target(c)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(c.args) == 0 {
+		return nil, "", &apiError{status: http.StatusBadRequest, hint: "missing cover variant"}
+	}
+	variant, err := enum.EnumByValue[enum.CoverType](enum.DefaultCoverType, enum.CoverType(c.args[0]))
+	if err != nil {
+		return nil, "", &apiError{status: http.StatusBadRequest, hint: fmt.Sprintf("unknown cover variant %s", c.args[0])}
+	}
+	return item, *variant, nil
+}
+
+func (s *Server) addCover(c *call) (*reply, error) {
+	item, variant, err := s.coverTarget(c)
+	if err != nil {
+		return nil, err
+	}
+	source := c.params.Get("url")
+	parsed, err := url.Parse(source)
+	if source == "" || err != nil || parsed.Host == "" {
+		return fail(http.StatusBadRequest, "missing or invalid url")
+	}
+	return ok(setCover(item, variant, Cover{
+		URL:         source,
+		Description: c.params.Get("description"),
+		Language:    c.params.Get("language"),
+	}))
+}
+
+func (s *Server) uploadCover(c *call) (*reply, error) {
+	item, variant, err := s.coverTarget(c)
+	if err != nil {
+		return nil, err
+	}
+	filename := c.params.Get("filename")
+	if filename == "" {
+		return fail(http.StatusBadRequest, "missing filename")
+	}
+	if len(c.body) == 0 {
+		return fail(http.StatusBadRequest, "missing image")
+	}
+	return ok(setCover(item, variant, Cover{
+		URL:         fmt.Sprintf("%s/%s/%s/%s-%s", generatedFilesURL, c.streamType, item.General.Hash, variant, filename),
+		Description: c.params.Get("description"),
+		Language:    c.params.Get("language"),
+		Image:       append([]byte(nil), c.body...),
+	}))
+}
+
+// Changes the description and, if given, the language of an existing cover.
+func (s *Server) updateCover(c *call) (*reply, error) {
+	item, variant, err := s.coverTarget(c)
+	if err != nil {
+		return nil, err
+	}
+	cover, exists := item.Covers[variant]
+	if !exists {
+		return fail(http.StatusNotFound, "no %s cover set", variant)
+	}
+	cover.Description = c.params.Get("description")
+	if language := c.params.Get("language"); language != "" {
+		cover.Language = language
+	}
+	return ok(setCover(item, variant, cover))
+}
+
+func (s *Server) removeCover(c *call) (*reply, error) {
+	item, variant, err := s.coverTarget(c)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := item.Covers[variant]; !exists {
+		return fail(http.StatusNotFound, "no %s cover set", variant)
+	}
+	delete(item.Covers, variant)
+	reflectCover(item, variant, Cover{})
+	return ok(reference(item))
+}
+
+func setCover(item *Item, variant enum.CoverType, cover Cover) omnia.CoverResult {
+	if item.Covers == nil {
+		item.Covers = map[enum.CoverType]Cover{}
+	}
+	item.Covers[variant] = cover
+	reflectCover(item, variant, cover)
+	return omnia.CoverResult{
+		ManagementResult: omnia.ManagementResult(reference(item)),
+		Variant:          variant,
+		URL:              cover.URL,
+		Description:      cover.Description,
+		Language:         cover.Language,
+	}
+}
+
+// Updates the image data of the item returned by the Media API. Banner and quad
+// covers have no description in the image data.
+func reflectCover(item *Item, variant enum.CoverType, cover Cover) {
+	data := &item.ImageData
+	switch variant {
+	case enum.DefaultCoverType:
+		data.Thumb, data.Description = cover.URL, cover.Description
+		if cover.Language != "" {
+			data.Language = cover.Language
+		}
+	case enum.ActionCoverType:
+		data.ThumbAction, data.DescriptionAction = cover.URL, cover.Description
+	case enum.BannerCoverType:
+		data.ThumbBanner = cover.URL
+	case enum.QuadCoverType:
+		data.ThumbQuad = cover.URL
+	case enum.AbtCoverType:
+		data.ThumbAbt, data.DescriptionAbt = cover.URL, cover.Description
+	}
+}
@@ -0,0 +1,125 @@
+package fakeserver
+
+import (
+	"encoding/json"
+	"os"
+
+	omnia "github.com/alex-berlin-tv/gomnia"
+	"github.com/alex-berlin-tv/gomnia/enum"
+)
+
+// A media item held by the fake server. Next to the data returned by the Media API
+// it contains the identifiers which aren't part of [omnia.MediaResultItem] and the
+// publishing state changed by the Management API.
+type Item struct {
+	omnia.MediaResultItem
+	// Found by the byslug operation.
+	Slug string `json:"slug,omitempty"`
+	// Found by the byremotereference operation.
+	RemoteReference string `json:"remotereference,omitempty"`
+	// Found by the bycodename operation.
+	CodeName string `json:"codename,omitempty"`
+	// Listed by the evergreens operation.
+	IsEvergreen bool `json:"isEvergreen,omitempty"`
+	// Set by the approve operation, reset by reject.
+	Approved bool `json:"approved,omitempty"`
+	// Set by the publish operation, reset by unpublish, reject, block, archive and
+	// delete.
+	Published bool `json:"published,omitempty"`
+	// Set by the reject operation, reset by approve.
+	Rejected bool `json:"rejected,omitempty"`
+	// Set by the block operation and by unpublish with BlockFuturePublishing,
+	// reset by unblock. Blocked items can't be published.
+	Blocked bool `json:"blocked,omitempty"`
+	// Set by the archive operation, reset by restore.
+	Archived bool `json:"archived,omitempty"`
+	// Set by a soft delete, reset by restore. Hard deleted items are removed.
+	Deleted bool `json:"deleted,omitempty"`
+	// Reason given with the last approve, reject, block or archive.
+	Reason string `json:"reason,omitempty"`
+	// URL of the source file of items created by the fromurl operation or set by
+	// the replacefile operation.
+	SourceURL string `json:"sourceUrl,omitempty"`
+	// Filename of the source file of items created by an upload.
+	Filename string `json:"filename,omitempty"`
+	// Content of the source file of items created by an upload or whose file was
+	// replaced by an upload.
+	File []byte `json:"-"`
+	// Processing state of the last replaced source file.
+	Processing enum.ProcessingState `json:"processing,omitempty"`
+	// Number of times the source file was replaced.
+	Replacements int `json:"replacements,omitempty"`
+	// Covers set by the cover operations. They are reflected in the image data
+	// returned by the Media API.
+	Covers map[enum.CoverType]Cover `json:"covers,omitempty"`
+	// All attributes set by the update operation.
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// Returns a deep copy of the item.
+func (i Item) clone() Item {
+	rsl := i
+	if i.Attributes != nil {
+		rsl.Attributes = make(map[string]string, len(i.Attributes))
+		for key, value := range i.Attributes {
+			rsl.Attributes[key] = value
+		}
+	}
+	if i.Covers != nil {
+		rsl.Covers = make(map[enum.CoverType]Cover, len(i.Covers))
+		for variant, cover := range i.Covers {
+			rsl.Covers[variant] = cover
+		}
+	}
+	rsl.ConnectedMedia.Shows = append([]omnia.MediaResultGeneral(nil), i.ConnectedMedia.Shows...)
+	return rsl
+}
+
+// A cover variant of an item.
+type Cover struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+	Language    string `json:"language,omitempty"`
+	// Content of an uploaded image.
+	Image []byte `json:"-"`
+}
+
+// An upload link created with the add operation of the upload links endpoint.
+type UploadLink struct {
+	Id                  int    `json:"ID"`
+	Hash                string `json:"hash"`
+	Title               string `json:"title"`
+	SelectedStreamtypes string `json:"selectedStreamtypes"`
+	Language            string `json:"language"`
+	MaxUsages           int    `json:"maxUsages,omitempty"`
+	Code                string `json:"code,omitempty"`
+}
+
+// The initial state of a fake server. Fixtures can be passed using [WithFixtures] or
+// loaded from a JSON file with [LoadFixtures].
+type Fixtures struct {
+	// Media items by stream type. Missing IDs, GIDs and hashes are generated.
+	Items map[enum.StreamType][]Item `json:"items,omitempty"`
+	// Channels returned by the channels operation of the Domain API.
+	Channels []omnia.MediaResultGeneral `json:"channels,omitempty"`
+	// Returned by the videocategories operation of the Domain API.
+	VideoCategories []omnia.MediaResultGeneral `json:"videocategories,omitempty"`
+	// Returned by the audiocategories operation of the Domain API.
+	AudioCategories []omnia.MediaResultGeneral `json:"audiocategories,omitempty"`
+	// Editable attributes by stream type. If set for a stream type, the update
+	// operation rejects all other attributes.
+	EditableAttributes map[enum.StreamType]omnia.EditableAttributesResponse `json:"editableattributes,omitempty"`
+	// Returned by the youtubecategories operation of the System API.
+	YouTubeCategories omnia.YouTubeCategories `json:"youtubecategories,omitempty"`
+}
+
+// Reads fixtures from a JSON file.
+func LoadFixtures(path string) (Fixtures, error) {
+	var rsl Fixtures
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rsl, err
+	}
+	err = json.Unmarshal(data, &rsl)
+	return rsl, err
+}
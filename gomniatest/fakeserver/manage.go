@@ -0,0 +1,471 @@
+package fakeserver
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	omnia "github.com/alex-berlin-tv/gomnia"
+	"github.com/alex-berlin-tv/gomnia/enum"
+)
+
+// Operations on a single item: manage/{streamType}/{id}/{operation}.
+var manageItemHandlers = map[string]handler{
+	"update":      (*Server).update,
+	"approve":     (*Server).approve,
+	"publish":     (*Server).publish,
+	"reject":      (*Server).reject,
+	"connectshow": (*Server).connectShow,
+	"unpublish":   (*Server).unpublish,
+	"block":       (*Server).block,
+	"unblock":     (*Server).unblock,
+	"archive":     (*Server).archive,
+	"restore":     (*Server).restore,
+	"remove":      (*Server).remove,
+	"replacefile": (*Server).replaceFile,
+	"addcover":    (*Server).addCover,
+	"uploadcover": (*Server).uploadCover,
+	"updatecover": (*Server).updateCover,
+	"removecover": (*Server).removeCover,
+}
+
+// Operations without an item: manage/{streamType}/{operation}.
+var manageHandlers = map[string]handler{
+	"add":     (*Server).add,
+	"fromurl": (*Server).fromURL,
+}
+
+var uploadLinkHandlers = map[string]handler{
+	"add": (*Server).addUploadLink,
+}
+
+var domainHandlers = map[string]handler{
+	"channels":        domainList(func(s *Server) []omnia.MediaResultGeneral { return s.channels }),
+	"videocategories": domainList(func(s *Server) []omnia.MediaResultGeneral { return s.videoCategories }),
+	"audiocategories": domainList(func(s *Server) []omnia.MediaResultGeneral { return s.audioCategories }),
+}
+
+var systemHandlers = map[string]handler{
+	"editableattributesfor": (*Server).editableAttributesFor,
+	"youtubecategories":     (*Server).youTubeCategoriesList,
+}
+
+// Base URL of the covers and waveforms generated by the fake.
+const generatedFilesURL = "https://fake.omnia.invalid/generated"
+
+// Parameters sent by gomnia with every request which are no item attributes.
+var pagingParams = []string{"start", "limit"}
+
+// Result of the Management API operations on an item.
+type itemReference struct {
+	Id   int    `json:"ID"`
+	Gid  int    `json:"GID"`
+	Hash string `json:"hash"`
+}
+
+func reference(item *Item) itemReference {
+	return itemReference{
+		Id:   item.General.Id,
+		Gid:  item.General.Gid,
+		Hash: item.General.Hash,
+	}
+}
+
+// Returns the item addressed by the Management API call.
+func (s *Server) target(c *call) (*Item, error) {
+	item := s.findItem(c.streamType, func(i *Item) bool { return i.General.Id == c.id })
+	if item == nil {
+		return nil, &apiError{status: http.StatusNotFound, hint: "item not found"}
+	}
+	return item, nil
+}
+
+// Sets the given attributes. Well-known attributes are reflected in the data
+// returned by the Media API, all attributes are kept in [Item.Attributes].
+func (s *Server) update(c *call) (*reply, error) {
+	item, err := s.target(c)
+	if err != nil {
+		return nil, err
+	}
+	editable := s.editableAttributes[c.streamType]
+	attributes := map[string]string{}
+	for key, values := range c.params {
+		if contains(pagingParams, key) || len(values) == 0 {
+			continue
+		}
+		if editable != nil {
+			property, ok := editable[key]
+			if !ok {
+				return fail(http.StatusBadRequest, "attribute %s is not editable", key)
+			}
+			if property.MaxLength > 0 && len(values[0]) > property.MaxLength {
+				return fail(http.StatusBadRequest, "attribute %s exceeds the maximal length of %d", key, property.MaxLength)
+			}
+		}
+		attributes[key] = values[0]
+	}
+	if len(attributes) == 0 {
+		return fail(http.StatusBadRequest, "no attributes given")
+	}
+	if channel, ok := attributes["channel"]; ok {
+		id, err := strconv.Atoi(channel)
+		if err != nil {
+			return fail(http.StatusBadRequest, "invalid channel %s", channel)
+		}
+		item.General.Channel = id
+	}
+	if item.Attributes == nil {
+		item.Attributes = map[string]string{}
+	}
+	for key, value := range attributes {
+		item.Attributes[key] = value
+		switch key {
+		case "title":
+			item.General.Title = value
+		case "subtitle":
+			item.General.Subtitle = value
+		case "description":
+			item.General.Description = value
+		case "genre":
+			item.General.Genre = value
+		case "refnr":
+			item.General.ReferenceNumber = value
+		case "slug":
+			item.Slug = value
+		case "remotereference":
+			item.RemoteReference = value
+		case "codename":
+			item.CodeName = value
+		}
+	}
+	return ok(reference(item))
+}
+
+func (s *Server) approve(c *call) (*reply, error) {
+	item, err := s.target(c)
+	if err != nil {
+		return nil, err
+	}
+	item.Approved = true
+	item.Rejected = false
+	item.Reason = c.params.Get("reason")
+	if aspects := c.params.Get("contentModerationAspects"); aspects != "" {
+		item.General.ContentModerationAspects = aspects
+	}
+	return ok(reference(item))
+}
+
+func (s *Server) publish(c *call) (*reply, error) {
+	item, err := s.target(c)
+	if err != nil {
+		return nil, err
+	}
+	if item.Rejected {
+		return fail(http.StatusBadRequest, "item is rejected and can't be published")
+	}
+	if item.Blocked {
+		return fail(http.StatusBadRequest, "item is blocked and can't be published")
+	}
+	if item.Archived || item.Deleted {
+		return fail(http.StatusBadRequest, "item is archived or deleted and can't be published")
+	}
+	item.Published = true
+	return ok(reference(item))
+}
+
+func (s *Server) reject(c *call) (*reply, error) {
+	item, err := s.target(c)
+	if err != nil {
+		return nil, err
+	}
+	item.Rejected = true
+	item.Approved = false
+	item.Published = false
+	item.Reason = c.params.Get("reason")
+	return ok(reference(item))
+}
+
+func (s *Server) unpublish(c *call) (*reply, error) {
+	item, err := s.target(c)
+	if err != nil {
+		return nil, err
+	}
+	item.Published = false
+	if c.params.Get("blockFuturePublishing") == string(enum.YesBool) {
+		item.Blocked = true
+	}
+	return ok(reference(item))
+}
+
+func (s *Server) block(c *call) (*reply, error) {
+	item, err := s.target(c)
+	if err != nil {
+		return nil, err
+	}
+	item.Blocked = true
+	item.Published = false
+	item.Reason = c.params.Get("reason")
+	return ok(reference(item))
+}
+
+func (s *Server) unblock(c *call) (*reply, error) {
+	item, err := s.target(c)
+	if err != nil {
+		return nil, err
+	}
+	if !item.Blocked {
+		return fail(http.StatusBadRequest, "item is not blocked")
+	}
+	item.Blocked = false
+	return ok(reference(item))
+}
+
+func (s *Server) archive(c *call) (*reply, error) {
+	item, err := s.target(c)
+	if err != nil {
+		return nil, err
+	}
+	if item.Deleted {
+		return fail(http.StatusBadRequest, "item is deleted")
+	}
+	item.Archived = true
+	item.Published = false
+	item.Reason = c.params.Get("reason")
+	return ok(reference(item))
+}
+
+func (s *Server) restore(c *call) (*reply, error) {
+	item, err := s.target(c)
+	if err != nil {
+		return nil, err
+	}
+	if !item.Archived && !item.Deleted {
+		return fail(http.StatusBadRequest, "item is neither archived nor deleted")
+	}
+	item.Archived = false
+	item.Deleted = false
+	return ok(reference(item))
+}
+
+// Moves the item to the trash or, in hard mode, removes it.
+func (s *Server) remove(c *call) (*reply, error) {
+	item, err := s.target(c)
+	if err != nil {
+		return nil, err
+	}
+	switch enum.DeleteMode(c.params.Get("mode")) {
+	case "", enum.SoftDelete:
+		item.Deleted = true
+		item.Published = false
+	case enum.HardDelete:
+		items := s.items[c.streamType]
+		for i, candidate := range items {
+			if candidate == item {
+				s.items[c.streamType] = append(items[:i:i], items[i+1:]...)
+				break
+			}
+		}
+	default:
+		return fail(http.StatusBadRequest, "invalid mode %s", c.params.Get("mode"))
+	}
+	return ok(reference(item))
+}
+
+// Connects the item with the show given as last path segment.
+func (s *Server) connectShow(c *call) (*reply, error) {
+	if c.streamType == enum.ShowStreamType {
+		return fail(http.StatusBadRequest, "shows can't be connected to a show")
+	}
+	item, err := s.target(c)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.args) == 0 {
+		return fail(http.StatusBadRequest, "missing show ID")
+	}
+	showId, err := strconv.Atoi(c.args[0])
+	if err != nil {
+		return fail(http.StatusBadRequest, "invalid show ID %s", c.args[0])
+	}
+	show := s.findItem(enum.ShowStreamType, func(i *Item) bool { return i.General.Id == showId })
+	if show == nil {
+		return fail(http.StatusNotFound, "show not found")
+	}
+	for _, connected := range item.ConnectedMedia.Shows {
+		if connected.Id == showId {
+			return ok(reference(item))
+		}
+	}
+	item.ConnectedMedia.Shows = append(item.ConnectedMedia.Shows, show.General)
+	return ok(reference(item))
+}
+
+// Adds a new channel. Only supported for the channels stream type.
+func (s *Server) add(c *call) (*reply, error) {
+	if c.streamType != "channels" {
+		return fail(http.StatusBadRequest, "unsupported operation %s for %s", c.operation, c.streamType)
+	}
+	title := c.params.Get("title")
+	if title == "" {
+		return fail(http.StatusBadRequest, "missing title")
+	}
+	channel := omnia.MediaResultGeneral{
+		Id:              s.nextId(),
+		Title:           title,
+		Subtitle:        c.params.Get("subtitle"),
+		Description:     c.params.Get("description"),
+		ReferenceNumber: c.params.Get("refnr"),
+	}
+	s.channels = append(s.channels, channel)
+	return created(itemReference{Id: channel.Id})
+}
+
+// Creates a new item from a remote file. The file itself isn't fetched, the URL is
+// kept in [Item.SourceURL].
+func (s *Server) fromURL(c *call) (*reply, error) {
+	if c.streamType != enum.VideoStreamType && c.streamType != enum.AudioStreamType {
+		return fail(http.StatusBadRequest, "unsupported operation %s for %s", c.operation, c.streamType)
+	}
+	source := c.params.Get("url")
+	parsed, err := url.Parse(source)
+	if source == "" || err != nil || parsed.Host == "" {
+		return fail(http.StatusBadRequest, "missing or invalid url")
+	}
+	channel := 0
+	if raw := c.params.Get("channel"); raw != "" {
+		if channel, err = strconv.Atoi(raw); err != nil {
+			return fail(http.StatusBadRequest, "invalid channel %s", raw)
+		}
+	}
+	title := c.params.Get("title")
+	if title == "" {
+		title = path.Base(parsed.Path)
+	}
+	item := Item{
+		MediaResultItem: omnia.MediaResultItem{
+			General: omnia.MediaResultGeneral{
+				Title:           title,
+				Subtitle:        c.params.Get("subtitle"),
+				Description:     c.params.Get("description"),
+				ReferenceNumber: c.params.Get("refnr"),
+				Channel:         channel,
+			},
+		},
+		SourceURL: source,
+		Published: c.params.Get("autoPublish") == string(enum.YesBool),
+	}
+	if notes := c.params.Get("notes"); notes != "" {
+		item.Attributes = map[string]string{"notes": notes}
+	}
+	return created(reference(s.addItem(c.streamType, item)))
+}
+
+// Replaces the source file of an item with a remote file. The file itself isn't
+// fetched, the URL is kept in [Item.SourceURL].
+func (s *Server) replaceFile(c *call) (*reply, error) {
+	if c.streamType != enum.VideoStreamType && c.streamType != enum.AudioStreamType {
+		return fail(http.StatusBadRequest, "unsupported operation %s for %s", c.operation, c.streamType)
+	}
+	item, err := s.target(c)
+	if err != nil {
+		return nil, err
+	}
+	source := c.params.Get("url")
+	parsed, err := url.Parse(source)
+	if source == "" || err != nil || parsed.Host == "" {
+		return fail(http.StatusBadRequest, "missing or invalid url")
+	}
+	filename := c.params.Get("filename")
+	if filename == "" {
+		filename = path.Base(parsed.Path)
+	}
+	item.SourceURL, item.Filename, item.File = source, filename, nil
+	return ok(s.replaceSource(c.streamType, item, c.params.Get("useQueue") == string(enum.YesBool), c.params.Get("regenerateCovers"), c.params.Get("regenerateWaveform")))
+}
+
+// Updates the processing state, covers and waveform of an item with a new source
+// file. Processing finishes immediately unless the item is queued.
+func (s *Server) replaceSource(streamType enum.StreamType, item *Item, queued bool, regenerateCovers, regenerateWaveform string) omnia.ProcessingStatus {
+	item.Processing = enum.FinishedProcessingState
+	if queued {
+		item.Processing = enum.QueuedProcessingState
+	}
+	item.Replacements++
+	rsl := omnia.ProcessingStatus{
+		ManagementResult:   omnia.ManagementResult(reference(item)),
+		State:              item.Processing,
+		RegenerateCovers:   enum.NoBool,
+		RegenerateWaveform: enum.NoBool,
+	}
+	if regenerateCovers == string(enum.YesBool) {
+		item.ImageData.Thumb = fmt.Sprintf("%s/%s/%s/cover-%d.jpg", generatedFilesURL, streamType, item.General.Hash, item.Replacements)
+		rsl.RegenerateCovers = enum.YesBool
+	}
+	if regenerateWaveform == string(enum.YesBool) && streamType == enum.AudioStreamType {
+		item.ImageData.Waveform = fmt.Sprintf("%s/%s/%s/waveform-%d.png", generatedFilesURL, streamType, item.General.Hash, item.Replacements)
+		rsl.RegenerateWaveform = enum.YesBool
+	}
+	return rsl
+}
+
+func (s *Server) addUploadLink(c *call) (*reply, error) {
+	for _, key := range []string{"title", "selectedStreamtypes", "language"} {
+		if c.params.Get(key) == "" {
+			return fail(http.StatusBadRequest, "missing %s", key)
+		}
+	}
+	maxUsages, _ := strconv.Atoi(c.params.Get("maxUsages"))
+	id := s.nextId()
+	link := UploadLink{
+		Id:                  id,
+		Hash:                hash("uploadlinks", id),
+		Title:               c.params.Get("title"),
+		SelectedStreamtypes: c.params.Get("selectedStreamtypes"),
+		Language:            c.params.Get("language"),
+		MaxUsages:           maxUsages,
+		Code:                c.params.Get("code"),
+	}
+	s.uploadLinks = append(s.uploadLinks, link)
+	return created(itemReference{Id: link.Id, Hash: link.Hash})
+}
+
+// Returns a handler listing the domain data returned by the given function.
+func domainList(data func(*Server) []omnia.MediaResultGeneral) handler {
+	return func(s *Server, c *call) (*reply, error) {
+		rsl := omnia.MediaResult{}
+		for _, item := range data(s) {
+			rsl = append(rsl, omnia.MediaResultItem{General: item})
+		}
+		return ok(rsl)
+	}
+}
+
+func (s *Server) editableAttributesFor(c *call) (*reply, error) {
+	if len(c.args) == 0 {
+		return fail(http.StatusBadRequest, "missing stream type")
+	}
+	attributes, exists := s.editableAttributes[enum.StreamType(c.args[0])]
+	if !exists {
+		attributes = omnia.EditableAttributesResponse{}
+	}
+	return ok(attributes)
+}
+
+func (s *Server) youTubeCategoriesList(c *call) (*reply, error) {
+	rsl := make(omnia.YouTubeCategories, len(s.youTubeCategories))
+	for id, name := range s.youTubeCategories {
+		rsl[id] = name
+	}
+	return ok(rsl)
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
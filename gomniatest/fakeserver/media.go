@@ -0,0 +1,255 @@
+package fakeserver
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	omnia "github.com/alex-berlin-tv/gomnia"
+	"github.com/alex-berlin-tv/gomnia/enum"
+)
+
+// Maximal and default number of items per page.
+const maxLimit = 100
+
+var mediaHandlers = map[string]handler{
+	"byid":              (*Server).byId,
+	"byglobalid":        (*Server).byGlobalId,
+	"byhash":            lookup(func(i *Item, arg string) bool { return i.General.Hash == arg }),
+	"byrefnr":           lookup(func(i *Item, arg string) bool { return i.General.ReferenceNumber == arg }),
+	"byslug":            lookup(func(i *Item, arg string) bool { return i.Slug == arg }),
+	"byremotereference": lookup(func(i *Item, arg string) bool { return i.RemoteReference == arg }),
+	"bycodename":        (*Server).byCodeName,
+	"all":               list(func(*Item) bool { return true }),
+	"latest":            (*Server).latest,
+	"picked":            list(func(i *Item) bool { return i.General.IsPicked == enum.YesBool }),
+	"evergreens":        list(func(i *Item) bool { return i.IsEvergreen }),
+	"forkids":           list(func(i *Item) bool { return i.General.ForKids == enum.YesBool }),
+	"byquery":           (*Server).byQuery,
+}
+
+func (s *Server) byId(c *call) (*reply, error) {
+	return s.byIntArg(c, func(i *Item, id int) bool { return i.General.Id == id })
+}
+
+func (s *Server) byGlobalId(c *call) (*reply, error) {
+	return s.byIntArg(c, func(i *Item, id int) bool { return i.General.Gid == id })
+}
+
+func (s *Server) byIntArg(c *call, match func(*Item, int) bool) (*reply, error) {
+	if len(c.args) == 0 {
+		return fail(http.StatusBadRequest, "missing ID")
+	}
+	id, err := strconv.Atoi(c.args[0])
+	if err != nil {
+		return fail(http.StatusBadRequest, "invalid ID %s", c.args[0])
+	}
+	return lookup(func(i *Item, _ string) bool { return match(i, id) })(s, c)
+}
+
+// Returns a handler for an operation returning the single item matching the
+// first argument.
+func lookup(match func(*Item, string) bool) handler {
+	return func(s *Server, c *call) (*reply, error) {
+		if len(c.args) == 0 || c.args[0] == "" {
+			return fail(http.StatusBadRequest, "missing argument for %s", c.operation)
+		}
+		item := s.findMediaItem(c.streamType, func(i *Item) bool { return match(i, c.args[0]) })
+		if item == nil {
+			return fail(http.StatusNotFound, "item not found")
+		}
+		return ok(item.MediaResultItem)
+	}
+}
+
+// Returns the container with its child media. For shows, the child media are all
+// items connected to the show.
+func (s *Server) byCodeName(c *call) (*reply, error) {
+	if !c.streamType.IsContainer() {
+		return fail(http.StatusBadRequest, "bycodename is only available for container streamtypes")
+	}
+	if len(c.args) == 0 || c.args[0] == "" {
+		return fail(http.StatusBadRequest, "missing argument for %s", c.operation)
+	}
+	item := s.findMediaItem(c.streamType, func(i *Item) bool { return i.CodeName == c.args[0] })
+	if item == nil {
+		return fail(http.StatusNotFound, "item not found")
+	}
+	rsl := omnia.ContainerResultItem{
+		MediaResultItem: item.MediaResultItem,
+		ChildMedia:      omnia.MediaResult{},
+	}
+	if c.streamType == enum.ShowStreamType {
+		for _, child := range s.mediaItems(enum.AllStreamType) {
+			for _, show := range child.ConnectedMedia.Shows {
+				if show.Id == item.General.Id {
+					rsl.ChildMedia = append(rsl.ChildMedia, child.MediaResultItem)
+					break
+				}
+			}
+		}
+	}
+	return ok(rsl)
+}
+
+// Returns the items of the stream type available in the Media API, archived and
+// deleted items are omitted.
+func (s *Server) mediaItems(streamType enum.StreamType) []*Item {
+	var rsl []*Item
+	for _, item := range s.itemsOf(streamType) {
+		if !item.Archived && !item.Deleted {
+			rsl = append(rsl, item)
+		}
+	}
+	return rsl
+}
+
+func (s *Server) findMediaItem(streamType enum.StreamType, match func(*Item) bool) *Item {
+	for _, item := range s.mediaItems(streamType) {
+		if match(item) {
+			return item
+		}
+	}
+	return nil
+}
+
+// Returns a handler for a listing of all items matching the filter.
+func list(filter func(*Item) bool) handler {
+	return func(s *Server, c *call) (*reply, error) {
+		return s.page(c, s.filter(c.streamType, filter))
+	}
+}
+
+func (s *Server) latest(c *call) (*reply, error) {
+	items := s.filter(c.streamType, func(*Item) bool { return true })
+	sort.SliceStable(items, func(i, j int) bool {
+		return time.Time(items[i].General.Created).After(time.Time(items[j].General.Created))
+	})
+	return s.page(c, items)
+}
+
+// Matches the words of the query case-insensitively against the title, subtitle
+// and description (or the given query fields) of the items. The classicwithand
+// mode requires all words to match, the other modes any of them. In the fulltext
+// mode each item is scored by the number of matching words.
+func (s *Server) byQuery(c *call) (*reply, error) {
+	if len(c.args) == 0 || c.args[0] == "" {
+		return fail(http.StatusBadRequest, "missing query")
+	}
+	words := strings.Fields(strings.ToLower(c.args[0]))
+	fields := []string{"title", "subtitle", "description"}
+	if value := c.params.Get("queryFields"); value != "" {
+		fields = strings.Split(value, ",")
+	}
+	mode := enum.QueryMode(c.params.Get("queryMode"))
+	substrings := c.params.Get("includeSubstringMatches") == string(enum.YesBool)
+	minimalScore, _ := strconv.ParseFloat(c.params.Get("minimalQueryScore"), 64)
+
+	var matches []*Item
+	for _, item := range s.mediaItems(c.streamType) {
+		score := 0
+		for _, word := range words {
+			if matchesWord(item, fields, word, substrings && mode != enum.FulltextQueryMode) {
+				score++
+			}
+		}
+		if score == 0 || (mode == enum.ClassicWithAndQueryMode && score < len(words)) {
+			continue
+		}
+		if mode == enum.FulltextQueryMode {
+			if float64(score) < minimalScore {
+				continue
+			}
+			scored := item.clone()
+			value := float64(score)
+			scored.General.QueryScore = &value
+			matches = append(matches, &scored)
+			continue
+		}
+		matches = append(matches, item)
+	}
+	if mode == enum.FulltextQueryMode {
+		sort.SliceStable(matches, func(i, j int) bool {
+			return *matches[i].General.QueryScore > *matches[j].General.QueryScore
+		})
+	}
+	return s.page(c, matches)
+}
+
+// Whether one of the fields of the item contains the word.
+func matchesWord(item *Item, fields []string, word string, substrings bool) bool {
+	for _, field := range fields {
+		var value string
+		switch strings.TrimSpace(field) {
+		case "title":
+			value = item.General.Title
+		case "subtitle":
+			value = item.General.Subtitle
+		case "description":
+			value = item.General.Description
+		default:
+			value = item.Attributes[field]
+		}
+		value = strings.ToLower(value)
+		if substrings {
+			if strings.Contains(value, word) {
+				return true
+			}
+			continue
+		}
+		for _, candidate := range strings.FieldsFunc(value, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+		}) {
+			if candidate == word {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Server) filter(streamType enum.StreamType, filter func(*Item) bool) []*Item {
+	var rsl []*Item
+	for _, item := range s.mediaItems(streamType) {
+		if filter(item) {
+			rsl = append(rsl, item)
+		}
+	}
+	return rsl
+}
+
+// Returns the page of the items selected by the start and limit parameters.
+func (s *Server) page(c *call, items []*Item) (*reply, error) {
+	start, limit := 0, maxLimit
+	if value := c.params.Get("start"); value != "" {
+		var err error
+		if start, err = strconv.Atoi(value); err != nil || start < 0 {
+			return fail(http.StatusBadRequest, "invalid start %s", value)
+		}
+	}
+	if value := c.params.Get("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 {
+			return fail(http.StatusBadRequest, "invalid limit %s", value)
+		}
+		if limit > maxLimit {
+			limit = maxLimit
+		}
+	}
+	rsl := omnia.MediaResult{}
+	for i := start; i < start+limit && i < len(items); i++ {
+		rsl = append(rsl, items[i].MediaResultItem)
+	}
+	return &reply{
+		status: http.StatusOK,
+		result: rsl,
+		paging: &omnia.ResponsePaging{
+			Start:       start,
+			Limit:       limit,
+			ResultCount: len(items),
+		},
+	}, nil
+}
@@ -0,0 +1,464 @@
+// Package fakeserver provides an in-memory fake of the nexxOMNIA API for integration
+// tests. It implements the URL schemes of the Media, Management, Domain and System
+// APIs used by gomnia, validates the request signature and session, keeps the state
+// of all items in memory and answers with the same response envelope (metadata,
+// result and paging) as omnia.
+//
+//	srv := fakeserver.New(fakeserver.WithItems(enum.AudioStreamType,
+//		fakeserver.Item{MediaResultItem: omnia.MediaResultItem{
+//			General: omnia.MediaResultGeneral{Title: "Episode 1"},
+//		}},
+//	))
+//	defer srv.Close()
+//	client := srv.Client()
+//	_, err := client.Approve(enum.AudioStreamType, 1, params.Approve{})
+//	item, _ := srv.Item(enum.AudioStreamType, 1)
+//	fmt.Println(item.Approved) // true
+//
+// The fake doesn't mimic every detail of omnia. The Media API for example returns
+// all items regardless of their publishing state.
+package fakeserver
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	omnia "github.com/alex-berlin-tv/gomnia"
+	"github.com/alex-berlin-tv/gomnia/enum"
+)
+
+// Credentials of the fake domain if not set otherwise with [WithCredentials].
+const (
+	DefaultDomainId  = "1"
+	DefaultApiSecret = "secret"
+	DefaultSessionId = "session"
+)
+
+// Version of the API reported in the response metadata.
+const apiVersion = "3.1"
+
+// Server is a running fake omnia API. All methods are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	domainId  string
+	apiSecret string
+	sessionId string
+
+	mutex              sync.Mutex
+	items              map[enum.StreamType][]*Item
+	channels           []omnia.MediaResultGeneral
+	videoCategories    []omnia.MediaResultGeneral
+	audioCategories    []omnia.MediaResultGeneral
+	editableAttributes map[enum.StreamType]omnia.EditableAttributesResponse
+	youTubeCategories  omnia.YouTubeCategories
+	uploadLinks        []UploadLink
+	uploads            map[string]*upload
+	requests           []Request
+	lastId             int
+}
+
+// Alters the configuration of a [Server].
+type Option func(*Server)
+
+// Use the given credentials for the fake domain. Requests signed with other
+// credentials are rejected.
+func WithCredentials(domainId, apiSecret, sessionId string) Option {
+	return func(s *Server) {
+		s.domainId = domainId
+		s.apiSecret = apiSecret
+		s.sessionId = sessionId
+	}
+}
+
+// Seed the server with the given items of a stream type.
+func WithItems(streamType enum.StreamType, items ...Item) Option {
+	return func(s *Server) {
+		for _, item := range items {
+			s.addItem(streamType, item)
+		}
+	}
+}
+
+// Seed the server with the given fixtures.
+func WithFixtures(fixtures Fixtures) Option {
+	return func(s *Server) {
+		for streamType, items := range fixtures.Items {
+			for _, item := range items {
+				s.addItem(streamType, item)
+			}
+		}
+		s.channels = append(s.channels, fixtures.Channels...)
+		s.videoCategories = append(s.videoCategories, fixtures.VideoCategories...)
+		s.audioCategories = append(s.audioCategories, fixtures.AudioCategories...)
+		for streamType, attributes := range fixtures.EditableAttributes {
+			s.editableAttributes[streamType] = attributes
+		}
+		for id, name := range fixtures.YouTubeCategories {
+			s.youTubeCategories[id] = name
+		}
+	}
+}
+
+// Starts a new fake server. Call [Server.Close] when done.
+func New(opts ...Option) *Server {
+	rsl := &Server{
+		domainId:           DefaultDomainId,
+		apiSecret:          DefaultApiSecret,
+		sessionId:          DefaultSessionId,
+		items:              map[enum.StreamType][]*Item{},
+		editableAttributes: map[enum.StreamType]omnia.EditableAttributesResponse{},
+		youTubeCategories:  omnia.YouTubeCategories{},
+		uploads:            map[string]*upload{},
+	}
+	for _, opt := range opts {
+		opt(rsl)
+	}
+	rsl.Server = httptest.NewServer(rsl)
+	return rsl
+}
+
+// Returns a client for the fake domain talking to the server. Further options can
+// be given, the base URL is always the one of the server.
+func (s *Server) Client(opts ...omnia.ClientOption) omnia.Client {
+	opts = append(opts, omnia.WithBaseURL(s.URL))
+	return omnia.NewClient(s.domainId, s.apiSecret, s.sessionId, opts...)
+}
+
+// Adds an item and returns it with the generated identifiers.
+func (s *Server) AddItem(streamType enum.StreamType, item Item) Item {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.addItem(streamType, item).clone()
+}
+
+// Returns a copy of the item with the given ID.
+func (s *Server) Item(streamType enum.StreamType, id int) (Item, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	item := s.findItem(streamType, func(i *Item) bool { return i.General.Id == id })
+	if item == nil {
+		return Item{}, false
+	}
+	return item.clone(), true
+}
+
+// Returns copies of all items of the stream type in the order they were added.
+func (s *Server) Items(streamType enum.StreamType) []Item {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	rsl := make([]Item, len(s.items[streamType]))
+	for i, item := range s.items[streamType] {
+		rsl[i] = item.clone()
+	}
+	return rsl
+}
+
+// Returns all channels of the domain.
+func (s *Server) Channels() []omnia.MediaResultGeneral {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]omnia.MediaResultGeneral(nil), s.channels...)
+}
+
+// Returns all upload links created so far.
+func (s *Server) UploadLinks() []UploadLink {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]UploadLink(nil), s.uploadLinks...)
+}
+
+// A request received by the server.
+type Request struct {
+	Method     string
+	Path       string
+	Class      omnia.ApiClass
+	StreamType enum.StreamType
+	Operation  string
+	Query      url.Values
+}
+
+// Returns all requests received so far, including rejected ones.
+func (s *Server) Requests() []Request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Returns the next free ID.
+func (s *Server) nextId() int {
+	s.lastId++
+	return s.lastId
+}
+
+func (s *Server) addItem(streamType enum.StreamType, item Item) *Item {
+	rsl := item.clone()
+	if rsl.General.Id == 0 {
+		rsl.General.Id = s.nextId()
+	} else if rsl.General.Id > s.lastId {
+		s.lastId = rsl.General.Id
+	}
+	if rsl.General.Gid == 0 {
+		rsl.General.Gid = 1000000 + rsl.General.Id
+	}
+	if rsl.General.Hash == "" {
+		rsl.General.Hash = hash(streamType, rsl.General.Id)
+	}
+	s.items[streamType] = append(s.items[streamType], &rsl)
+	return &rsl
+}
+
+// Returns the first item of the stream type matching the predicate. The allmedia
+// stream type searches all items.
+func (s *Server) findItem(streamType enum.StreamType, match func(*Item) bool) *Item {
+	for _, item := range s.itemsOf(streamType) {
+		if match(item) {
+			return item
+		}
+	}
+	return nil
+}
+
+// Returns the items of the stream type. The allmedia stream type returns the items
+// of all stream types except shows.
+func (s *Server) itemsOf(streamType enum.StreamType) []*Item {
+	if streamType != enum.AllStreamType {
+		return s.items[streamType]
+	}
+	var rsl []*Item
+	for _, other := range []enum.StreamType{enum.VideoStreamType, enum.AudioStreamType} {
+		rsl = append(rsl, s.items[other]...)
+	}
+	return rsl
+}
+
+// Generates a hash for a new item.
+func hash(streamType enum.StreamType, id int) string {
+	prefix := strings.ToUpper(string(streamType))
+	if len(prefix) > 2 {
+		prefix = prefix[:2]
+	}
+	return fmt.Sprintf("%s%d%06d", prefix, id, id*7919%1000000)
+}
+
+// A parsed request.
+type call struct {
+	method     string
+	class      omnia.ApiClass
+	streamType enum.StreamType
+	operation  string
+	// The item addressed by a Management API call, zero if none.
+	id int
+	// Remaining path segments after the operation.
+	args   []string
+	params url.Values
+	// Raw body of the request, used by uploads.
+	body []byte
+}
+
+// The answer of a handler.
+type reply struct {
+	status int
+	result interface{}
+	paging *omnia.ResponsePaging
+}
+
+func ok(result interface{}) (*reply, error) {
+	return &reply{status: http.StatusOK, result: result}, nil
+}
+
+func created(result interface{}) (*reply, error) {
+	return &reply{status: http.StatusCreated, result: result}, nil
+}
+
+// A failed call, answered with the given status and error hint.
+type apiError struct {
+	status int
+	hint   string
+}
+
+func (e *apiError) Error() string {
+	return e.hint
+}
+
+func fail(status int, format string, args ...interface{}) (*reply, error) {
+	return nil, &apiError{status: status, hint: fmt.Sprintf(format, args...)}
+}
+
+type handler func(s *Server, c *call) (*reply, error)
+
+// Handles all requests to the fake API.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		s.write(w, r, &call{}, nil, &apiError{status: http.StatusBadRequest, hint: "invalid parameters"})
+		return
+	}
+	c, h, err := s.route(r)
+	if err == nil && r.Body != nil {
+		if c.body, err = io.ReadAll(r.Body); err != nil {
+			err = &apiError{status: http.StatusBadRequest, hint: "couldn't read body"}
+		}
+	}
+	s.mutex.Lock()
+	s.requests = append(s.requests, Request{
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Class:      c.class,
+		StreamType: c.streamType,
+		Operation:  c.operation,
+		Query:      r.Form,
+	})
+	s.mutex.Unlock()
+	if err != nil {
+		s.write(w, r, c, nil, err)
+		return
+	}
+	if err := s.authenticate(r, c); err != nil {
+		s.write(w, r, c, nil, err)
+		return
+	}
+	s.mutex.Lock()
+	rsp, err := h(s, c)
+	s.mutex.Unlock()
+	s.write(w, r, c, rsp, err)
+}
+
+// Parses the URL of the request according to the URL schemes of the API classes
+// and returns the handler of the operation.
+func (s *Server) route(r *http.Request) (*call, handler, error) {
+	c := &call{method: r.Method, params: r.Form}
+	// Split the escaped path as arguments (like a query) might contain slashes.
+	parts := strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/")
+	for i, part := range parts {
+		if unescaped, err := url.PathUnescape(part); err == nil {
+			parts[i] = unescaped
+		}
+	}
+	if len(parts) < 3 {
+		return c, nil, &apiError{status: http.StatusNotFound, hint: "unknown endpoint"}
+	}
+	if parts[0] != s.domainId {
+		return c, nil, &apiError{status: http.StatusNotFound, hint: "unknown domain"}
+	}
+	var handlers map[string]handler
+	switch parts[1] {
+	case "domain":
+		c.class, c.operation, c.args = omnia.DomainApiClass, parts[2], parts[3:]
+		handlers = domainHandlers
+	case "system":
+		c.class, c.operation, c.args = omnia.SystemApiClass, parts[2], parts[3:]
+		handlers = systemHandlers
+	case "manage":
+		c.class = omnia.ManagementApiClass
+		if parts[2] == "uploadlinks" {
+			if len(parts) < 4 {
+				return c, nil, &apiError{status: http.StatusNotFound, hint: "unknown endpoint"}
+			}
+			c.operation, c.args = parts[3], parts[4:]
+			handlers = uploadLinkHandlers
+			break
+		}
+		if len(parts) < 4 {
+			return c, nil, &apiError{status: http.StatusNotFound, hint: "unknown endpoint"}
+		}
+		c.streamType = enum.StreamType(parts[2])
+		if parts[3] == "upload" {
+			// Scheme of the chunked upload: manage/{streamType}/upload/{operation}
+			if len(parts) < 5 {
+				return c, nil, &apiError{status: http.StatusNotFound, hint: "unknown endpoint"}
+			}
+			c.operation, c.args = parts[4], parts[5:]
+			handlers = uploadHandlers
+		} else if id, err := strconv.Atoi(parts[3]); err == nil {
+			// Scheme of operations on a single item: manage/{streamType}/{id}/{operation}
+			if len(parts) < 5 {
+				return c, nil, &apiError{status: http.StatusNotFound, hint: "unknown endpoint"}
+			}
+			c.id, c.operation, c.args = id, parts[4], parts[5:]
+			handlers = manageItemHandlers
+		} else {
+			c.operation, c.args = parts[3], parts[4:]
+			handlers = manageHandlers
+		}
+	default:
+		c.class = omnia.MediaApiClass
+		c.streamType, c.operation, c.args = enum.StreamType(parts[1]), parts[2], parts[3:]
+		handlers = mediaHandlers
+	}
+	h, ok := handlers[c.operation]
+	if !ok {
+		return c, nil, &apiError{status: http.StatusBadRequest, hint: fmt.Sprintf("unsupported operation %s", c.operation)}
+	}
+	return c, h, nil
+}
+
+// Checks the signature of the request and, for the Management API, the session.
+func (s *Server) authenticate(r *http.Request, c *call) error {
+	signature := md5.Sum([]byte(c.operation + s.domainId + s.apiSecret))
+	if r.Header.Get("X-Request-Token") != hex.EncodeToString(signature[:]) {
+		return &apiError{status: http.StatusUnauthorized, hint: "invalid Token"}
+	}
+	if c.class == omnia.ManagementApiClass && r.Header.Get("X-Request-CID") != s.sessionId {
+		return &apiError{status: http.StatusUnauthorized, hint: "invalid Session"}
+	}
+	return nil
+}
+
+// The response envelope of omnia. Encoded using [wire].
+type envelope struct {
+	Metadata omnia.ResponseMetadata `json:"metadata"`
+	Result   interface{}            `json:"result"`
+	Paging   *omnia.ResponsePaging  `json:"paging,omitempty"`
+}
+
+func (s *Server) write(w http.ResponseWriter, r *http.Request, c *call, rsp *reply, err error) {
+	calledWith := r.URL.Path
+	if r.URL.RawQuery != "" {
+		calledWith = fmt.Sprintf("%s?%s", calledWith, r.URL.RawQuery)
+	}
+	domainId, _ := strconv.Atoi(s.domainId)
+	fromStage := 0
+	fromCache := 0
+	notice := ""
+	body := envelope{
+		Metadata: omnia.ResponseMetadata{
+			Status:         http.StatusOK,
+			ApiVersion:     apiVersion,
+			Verb:           r.Method,
+			ProcessingTime: 0.001,
+			CalledWith:     &calledWith,
+			CalledFor:      &c.operation,
+			ForDomain:      &domainId,
+			FromStage:      &fromStage,
+			Notice:         &notice,
+			FromCache:      &fromCache,
+		},
+	}
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		body.Metadata.Status = apiErr.status
+		body.Metadata.ErrorHint = &apiErr.hint
+	} else if err != nil {
+		hint := err.Error()
+		body.Metadata.Status = http.StatusInternalServerError
+		body.Metadata.ErrorHint = &hint
+	} else {
+		body.Metadata.Status = rsp.status
+		body.Result = rsp.result
+		body.Paging = rsp.paging
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(body.Metadata.Status)
+	_ = json.NewEncoder(w).Encode(wire(reflect.ValueOf(body)))
+}
@@ -0,0 +1,252 @@
+package fakeserver_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	omnia "github.com/alex-berlin-tv/gomnia"
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/alex-berlin-tv/gomnia/gomniatest/fakeserver"
+	"github.com/alex-berlin-tv/gomnia/params"
+)
+
+// Returns n items titled "Item 1" to "Item n".
+func items(n int) []fakeserver.Item {
+	rsl := make([]fakeserver.Item, n)
+	for i := range rsl {
+		rsl[i].General.Title = fmt.Sprintf("Item %d", i+1)
+	}
+	return rsl
+}
+
+func newServer(t *testing.T, opts ...fakeserver.Option) *fakeserver.Server {
+	t.Helper()
+	srv := fakeserver.New(opts...)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func item(t *testing.T, srv *fakeserver.Server, streamType enum.StreamType, id int) fakeserver.Item {
+	t.Helper()
+	rsl, ok := srv.Item(streamType, id)
+	if !ok {
+		t.Fatalf("%s item %d not found", streamType, id)
+	}
+	return rsl
+}
+
+func TestApprovePublishReject(t *testing.T) {
+	srv := newServer(t, fakeserver.WithItems(enum.AudioStreamType, items(1)...))
+	client := srv.Client()
+
+	if _, err := client.Approve(enum.AudioStreamType, 1, params.Approve{Reason: "fine"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := item(t, srv, enum.AudioStreamType, 1); !got.Approved || got.Reason != "fine" {
+		t.Fatalf("item not approved: %+v", got)
+	}
+	if _, err := client.Publish(enum.AudioStreamType, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := item(t, srv, enum.AudioStreamType, 1); !got.Published {
+		t.Fatal("item not published")
+	}
+	if _, err := client.Reject(enum.AudioStreamType, 1, params.Reject{Reason: "off topic"}); err != nil {
+		t.Fatal(err)
+	}
+	got := item(t, srv, enum.AudioStreamType, 1)
+	if !got.Rejected || got.Approved || got.Published || got.Reason != "off topic" {
+		t.Fatalf("item not rejected: %+v", got)
+	}
+	if _, err := client.Publish(enum.AudioStreamType, 1); !errors.Is(err, omnia.ErrValidation) {
+		t.Fatalf("publishing a rejected item returned %v, want ErrValidation", err)
+	}
+	if _, err := client.Approve(enum.AudioStreamType, 2, params.Approve{}); !errors.Is(err, omnia.ErrNotFound) {
+		t.Fatalf("approving a missing item returned %v, want ErrNotFound", err)
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	srv := newServer(t, fakeserver.WithItems(enum.VideoStreamType, items(1)...))
+	client := srv.Client()
+
+	if _, err := client.Unpublish(enum.VideoStreamType, 1, params.Unpublish{BlockFuturePublishing: enum.YesBool}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Publish(enum.VideoStreamType, 1); !errors.Is(err, omnia.ErrValidation) {
+		t.Fatalf("publishing a blocked item returned %v, want ErrValidation", err)
+	}
+	if _, err := client.Unblock(enum.VideoStreamType, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Archive(enum.VideoStreamType, 1, params.Archive{Reason: "outdated"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ById(enum.VideoStreamType, 1, nil); !errors.Is(err, omnia.ErrNotFound) {
+		t.Fatalf("archived item returned %v, want ErrNotFound", err)
+	}
+	if _, err := client.Restore(enum.VideoStreamType, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ById(enum.VideoStreamType, 1, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Delete(enum.VideoStreamType, 1, params.Delete{Mode: enum.HardDelete}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := srv.Item(enum.VideoStreamType, 1); ok {
+		t.Fatal("hard deleted item still exists")
+	}
+}
+
+func TestConnectShow(t *testing.T) {
+	srv := newServer(t,
+		fakeserver.WithItems(enum.AudioStreamType, items(1)...),
+		fakeserver.WithItems(enum.ShowStreamType, fakeserver.Item{MediaResultItem: omnia.MediaResultItem{
+			General: omnia.MediaResultGeneral{Title: "Show"},
+		}}),
+	)
+	client := srv.Client()
+
+	if _, err := client.ConnectShow(enum.AudioStreamType, 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	shows := item(t, srv, enum.AudioStreamType, 1).ConnectedMedia.Shows
+	if len(shows) != 1 || shows[0].Id != 2 || shows[0].Title != "Show" {
+		t.Fatalf("unexpected connected shows %+v", shows)
+	}
+	if _, err := client.ConnectShow(enum.AudioStreamType, 1, 3); !errors.Is(err, omnia.ErrNotFound) {
+		t.Fatalf("connecting a missing show returned %v, want ErrNotFound", err)
+	}
+}
+
+func TestAddChannel(t *testing.T) {
+	srv := newServer(t)
+	client := srv.Client()
+
+	if _, err := client.AddChannel(params.Channel{Title: "News", Refnr: "news"}); err != nil {
+		t.Fatal(err)
+	}
+	channels := srv.Channels()
+	if len(channels) != 1 || channels[0].Title != "News" {
+		t.Fatalf("unexpected channels %+v", channels)
+	}
+	rsl, err := client.Channels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rsl.Result) != 1 || rsl.Result[0].General.Title != "News" {
+		t.Fatalf("unexpected channels %+v", rsl.Result)
+	}
+}
+
+func TestAddUploadLink(t *testing.T) {
+	srv := newServer(t)
+	client := srv.Client()
+
+	if _, err := client.AddUploadLink(params.UploadLink{
+		Title:               "Contributions",
+		SelectedStreamtypes: "video,audio",
+		Language:            "de",
+		MaxUsages:           5,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	links := srv.UploadLinks()
+	if len(links) != 1 {
+		t.Fatalf("got %d upload links, want 1", len(links))
+	}
+	if link := links[0]; link.Title != "Contributions" || link.SelectedStreamtypes != "video,audio" || link.Language != "de" || link.MaxUsages != 5 {
+		t.Fatalf("unexpected upload link %+v", link)
+	}
+}
+
+func TestCovers(t *testing.T) {
+	srv := newServer(t, fakeserver.WithItems(enum.VideoStreamType, items(1)...))
+	client := srv.Client()
+
+	rsl, err := client.SetCoverFromURL(enum.VideoStreamType, 1, enum.DefaultCoverType, params.CoverFromURL{
+		URL:         "https://example.com/cover.jpg",
+		Description: "Cover",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsl.Result.URL != "https://example.com/cover.jpg" || rsl.Result.Variant != enum.DefaultCoverType {
+		t.Fatalf("unexpected cover %+v", rsl.Result)
+	}
+	image := []byte("banner image")
+	if _, err := client.UploadCover(enum.VideoStreamType, 1, enum.BannerCoverType, bytes.NewReader(image), params.CoverUpload{Filename: "banner.jpg"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.SetCoverDescription(enum.VideoStreamType, 1, enum.DefaultCoverType, params.CoverDescription{Description: "New"}); err != nil {
+		t.Fatal(err)
+	}
+	got := item(t, srv, enum.VideoStreamType, 1)
+	if got.Covers[enum.DefaultCoverType].Description != "New" || !bytes.Equal(got.Covers[enum.BannerCoverType].Image, image) {
+		t.Fatalf("unexpected covers %+v", got.Covers)
+	}
+	media, err := client.ById(enum.VideoStreamType, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if media.Result.ImageData.Thumb != "https://example.com/cover.jpg" || media.Result.ImageData.Description != "New" {
+		t.Fatalf("cover not reflected in the image data %+v", media.Result.ImageData)
+	}
+	if _, err := client.RemoveCover(enum.VideoStreamType, 1, enum.BannerCoverType); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.RemoveCover(enum.VideoStreamType, 1, enum.BannerCoverType); !errors.Is(err, omnia.ErrNotFound) {
+		t.Fatalf("removing a missing cover returned %v, want ErrNotFound", err)
+	}
+}
+
+func TestWrongCredentials(t *testing.T) {
+	srv := newServer(t, fakeserver.WithItems(enum.AudioStreamType, items(1)...))
+
+	wrongSecret := omnia.NewClient(fakeserver.DefaultDomainId, "wrong", fakeserver.DefaultSessionId, omnia.WithBaseURL(srv.URL))
+	if _, err := wrongSecret.ById(enum.AudioStreamType, 1, nil); !errors.Is(err, omnia.ErrUnauthorized) {
+		t.Fatalf("media call with a wrong secret returned %v, want ErrUnauthorized", err)
+	}
+	if _, err := wrongSecret.Publish(enum.AudioStreamType, 1); !errors.Is(err, omnia.ErrUnauthorized) {
+		t.Fatalf("management call with a wrong secret returned %v, want ErrUnauthorized", err)
+	}
+	wrongSession := omnia.NewClient(fakeserver.DefaultDomainId, fakeserver.DefaultApiSecret, "wrong", omnia.WithBaseURL(srv.URL))
+	if _, err := wrongSession.Publish(enum.AudioStreamType, 1); !errors.Is(err, omnia.ErrUnauthorized) {
+		t.Fatalf("management call with a wrong session returned %v, want ErrUnauthorized", err)
+	}
+	if item(t, srv, enum.AudioStreamType, 1).Published {
+		t.Fatal("unauthorized call changed the item")
+	}
+}
+
+func TestPaging(t *testing.T) {
+	srv := newServer(t, fakeserver.WithItems(enum.AudioStreamType, items(250)...))
+	client := srv.Client()
+
+	page, err := client.All(enum.AudioStreamType, params.Basic{Limit: 10, Start: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Result) != 10 || page.Result[0].General.Id != 21 || page.Result[9].General.Id != 30 {
+		t.Fatalf("unexpected page %d items starting at %d", len(page.Result), page.Result[0].General.Id)
+	}
+	if page.Paging.Start != 20 || page.Paging.Limit != 10 || page.Paging.ResultCount != 250 {
+		t.Fatalf("unexpected paging %+v", page.Paging)
+	}
+
+	all, err := client.AllPaged(enum.AudioStreamType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all.Result) != 250 {
+		t.Fatalf("got %d items, want 250", len(all.Result))
+	}
+	for i, got := range all.Result {
+		if got.General.Id != i+1 {
+			t.Fatalf("item %d has ID %d, want %d", i, got.General.Id, i+1)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package fakeserver
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	omnia "github.com/alex-berlin-tv/gomnia"
+	"github.com/alex-berlin-tv/gomnia/enum"
+)
+
+// Operations of the chunked upload: manage/{streamType}/upload/{operation}.
+var uploadHandlers = map[string]handler{
+	"start":  (*Server).startUpload,
+	"status": (*Server).uploadStatus,
+	"chunk":  (*Server).uploadChunk,
+	"finish": (*Server).finishUpload,
+}
+
+// An upload in progress.
+type upload struct {
+	id         string
+	streamType enum.StreamType
+	size       int64
+	chunkSize  int64
+	checksum   string
+	params     map[string]string
+	chunks     map[int][]byte
+}
+
+func (u *upload) count() int {
+	return int((u.size + u.chunkSize - 1) / u.chunkSize)
+}
+
+func (u *upload) session() omnia.UploadSession {
+	received := make([]int, 0, len(u.chunks))
+	for index := range u.chunks {
+		received = append(received, index)
+	}
+	sort.Ints(received)
+	return omnia.UploadSession{
+		UploadId:       u.id,
+		ChunkSize:      u.chunkSize,
+		Chunks:         u.count(),
+		ReceivedChunks: received,
+	}
+}
+
+// Returns the upload addressed by the first argument of the call.
+func (s *Server) uploadOf(c *call) (*upload, error) {
+	if len(c.args) == 0 {
+		return nil, &apiError{status: http.StatusBadRequest, hint: "missing upload id"}
+	}
+	rsl, ok := s.uploads[c.args[0]]
+	if !ok || rsl.streamType != c.streamType {
+		return nil, &apiError{status: http.StatusNotFound, hint: "upload not found"}
+	}
+	return rsl, nil
+}
+
+func (s *Server) startUpload(c *call) (*reply, error) {
+	if c.streamType != enum.VideoStreamType && c.streamType != enum.AudioStreamType {
+		return fail(http.StatusBadRequest, "unsupported operation %s for %s", c.operation, c.streamType)
+	}
+	for _, key := range []string{"filename", "filesize", "chunkSize", "checksum"} {
+		if c.params.Get(key) == "" {
+			return fail(http.StatusBadRequest, "missing %s", key)
+		}
+	}
+	size, err := strconv.ParseInt(c.params.Get("filesize"), 10, 64)
+	if err != nil || size <= 0 {
+		return fail(http.StatusBadRequest, "invalid filesize %s", c.params.Get("filesize"))
+	}
+	chunkSize, err := strconv.ParseInt(c.params.Get("chunkSize"), 10, 64)
+	if err != nil || chunkSize <= 0 {
+		return fail(http.StatusBadRequest, "invalid chunkSize %s", c.params.Get("chunkSize"))
+	}
+	if raw := c.params.Get("item"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			return fail(http.StatusBadRequest, "invalid item %s", raw)
+		}
+		if s.findItem(c.streamType, func(i *Item) bool { return i.General.Id == id }) == nil {
+			return fail(http.StatusNotFound, "item not found")
+		}
+	}
+	rsl := &upload{
+		id:         fmt.Sprintf("upload-%d", s.nextId()),
+		streamType: c.streamType,
+		size:       size,
+		chunkSize:  chunkSize,
+		checksum:   c.params.Get("checksum"),
+		params:     map[string]string{},
+		chunks:     map[int][]byte{},
+	}
+	for key := range c.params {
+		rsl.params[key] = c.params.Get(key)
+	}
+	s.uploads[rsl.id] = rsl
+	return created(rsl.session())
+}
+
+func (s *Server) uploadStatus(c *call) (*reply, error) {
+	upload, err := s.uploadOf(c)
+	if err != nil {
+		return nil, err
+	}
+	return ok(upload.session())
+}
+
+// Stores a chunk. The chunk has to have the expected size and match the checksum
+// given as parameter.
+func (s *Server) uploadChunk(c *call) (*reply, error) {
+	upload, err := s.uploadOf(c)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.args) < 2 {
+		return fail(http.StatusBadRequest, "missing chunk index")
+	}
+	index, err := strconv.Atoi(c.args[1])
+	if err != nil || index < 0 || index >= upload.count() {
+		return fail(http.StatusBadRequest, "invalid chunk index %s", c.args[1])
+	}
+	expected := upload.chunkSize
+	if index == upload.count()-1 {
+		expected = upload.size - int64(index)*upload.chunkSize
+	}
+	if int64(len(c.body)) != expected {
+		return fail(http.StatusBadRequest, "chunk %d has %d bytes, expected %d", index, len(c.body), expected)
+	}
+	if checksum := c.params.Get("checksum"); checksum != "" && checksum != md5Hex(c.body) {
+		return fail(http.StatusBadRequest, "checksum mismatch for chunk %d", index)
+	}
+	upload.chunks[index] = append([]byte(nil), c.body...)
+	return ok(upload.session())
+}
+
+// Assembles the chunks and creates the item if the checksum of the file matches
+// the one given on start.
+func (s *Server) finishUpload(c *call) (*reply, error) {
+	upload, err := s.uploadOf(c)
+	if err != nil {
+		return nil, err
+	}
+	if len(upload.chunks) != upload.count() {
+		return fail(http.StatusBadRequest, "received %d of %d chunks", len(upload.chunks), upload.count())
+	}
+	var file bytes.Buffer
+	for index := 0; index < upload.count(); index++ {
+		file.Write(upload.chunks[index])
+	}
+	checksum := md5Hex(file.Bytes())
+	if checksum != upload.checksum {
+		return fail(http.StatusBadRequest, "checksum mismatch, received %s", checksum)
+	}
+	if raw, ok := upload.params["item"]; ok {
+		return s.finishReplacement(c, upload, raw, file.Bytes(), checksum)
+	}
+	delete(s.uploads, upload.id)
+	channel, _ := strconv.Atoi(upload.params["channel"])
+	title := upload.params["title"]
+	if title == "" {
+		title = upload.params["filename"]
+	}
+	item := Item{
+		MediaResultItem: omnia.MediaResultItem{
+			General: omnia.MediaResultGeneral{
+				Title:           title,
+				Subtitle:        upload.params["subtitle"],
+				Description:     upload.params["description"],
+				ReferenceNumber: upload.params["refnr"],
+				Channel:         channel,
+			},
+		},
+		Filename:  upload.params["filename"],
+		File:      file.Bytes(),
+		Published: upload.params["autoPublish"] == string(enum.YesBool),
+	}
+	if notes := upload.params["notes"]; notes != "" {
+		item.Attributes = map[string]string{"notes": notes}
+	}
+	added := s.addItem(c.streamType, item)
+	return created(omnia.UploadResult{
+		ManagementResult: omnia.ManagementResult(reference(added)),
+		Checksum:         checksum,
+	})
+}
+
+// Replaces the source file of the item given on start with the uploaded file.
+func (s *Server) finishReplacement(c *call, upload *upload, raw string, file []byte, checksum string) (*reply, error) {
+	id, err := strconv.Atoi(raw)
+	if err != nil {
+		return fail(http.StatusBadRequest, "invalid item %s", raw)
+	}
+	item := s.findItem(c.streamType, func(i *Item) bool { return i.General.Id == id })
+	if item == nil {
+		return fail(http.StatusNotFound, "item not found")
+	}
+	delete(s.uploads, upload.id)
+	item.SourceURL, item.Filename, item.File = "", upload.params["filename"], file
+	rsl := s.replaceSource(c.streamType, item, false, upload.params["regenerateCovers"], upload.params["regenerateWaveform"])
+	rsl.Checksum = checksum
+	return ok(rsl)
+}
+
+func md5Hex(data []byte) string {
+	sum := md5.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,128 @@
+package fakeserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/alex-berlin-tv/gomnia/types"
+)
+
+var (
+	boolType      = reflect.TypeOf(enum.Bool(""))
+	unixTSType    = reflect.TypeOf(types.UnixTS{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// Converts a value into the representation used by omnia on the wire. Other than the
+// JSON encoding of the library types, booleans ([enum.Bool]) are sent as numbers and
+// dates ([types.UnixTS]) as UNIX timestamps. Unset values are sent as 0. Structs are
+// converted to maps honoring the JSON tags of their fields, the result is meant to be
+// encoded with encoding/json.
+func wire(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	switch v.Type() {
+	case boolType:
+		if v.String() == "" {
+			return json.Number(enum.NoBool)
+		}
+		return json.Number(v.String())
+	case unixTSType:
+		ts := time.Time(v.Interface().(types.UnixTS))
+		if ts.IsZero() {
+			return 0
+		}
+		return ts.Unix()
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return wire(v.Elem())
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		fallthrough
+	case reflect.Array:
+		rsl := make([]interface{}, v.Len())
+		for i := range rsl {
+			rsl[i] = wire(v.Index(i))
+		}
+		return rsl
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		rsl := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			rsl[fmt.Sprint(iter.Key().Interface())] = wire(iter.Value())
+		}
+		return rsl
+	case reflect.Struct:
+		if v.Type().Implements(marshalerType) {
+			return v.Interface()
+		}
+		rsl := map[string]interface{}{}
+		wireFields(v, rsl)
+		return rsl
+	}
+	return v.Interface()
+}
+
+// Adds the fields of a struct to the map. Fields of embedded structs are added
+// first so fields of the outer struct take precedence.
+func wireFields(v reflect.Value, rsl map[string]interface{}) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" && field.Type.Kind() == reflect.Struct {
+			wireFields(v.Field(i), rsl)
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() || (field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct) {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		value := v.Field(i)
+		if strings.Contains(","+options+",", ",omitempty,") && isEmpty(value) {
+			continue
+		}
+		rsl[name] = wire(value)
+	}
+}
+
+// Same rules as the omitempty option of encoding/json.
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package recorder
+
+import (
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// A cassette holds all recorded interactions of a test. It's stored as a JSON file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// A recorded request and the response of omnia.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// The parts of a request used to match it during replay.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// The normalized query string, see [NormalizeQuery].
+	Query string `json:"query"`
+}
+
+// A recorded response.
+type RecordedResponse struct {
+	StatusCode int                 `json:"status_code"`
+	Header     map[string][]string `json:"header,omitempty"`
+	Body       string              `json:"body"`
+}
+
+// Reads a cassette from the given file.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rsl Cassette
+	if err := json.Unmarshal(data, &rsl); err != nil {
+		return nil, err
+	}
+	return &rsl, nil
+}
+
+// Writes the cassette to the given file. Missing directories are created.
+func (c Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Returns the query string with sorted keys and values. The given parameters are
+// removed from the query.
+func NormalizeQuery(query url.Values, ignore ...string) string {
+	normalized := url.Values{}
+	for key, values := range query {
+		if contains(ignore, key) {
+			continue
+		}
+		sorted := append([]string(nil), values...)
+		sort.Strings(sorted)
+		normalized[key] = sorted
+	}
+	return normalized.Encode()
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,328 @@
+// Package recorder records real requests to the omnia API and their responses to
+// fixture files (cassettes) and replays them deterministically. This allows testing
+// code built on top of gomnia without live credentials. Secrets (the request token,
+// the session id, the API secret and `secret` attributes) are scrubbed before a
+// cassette is written. This includes the values of the request token, the session
+// and `secret` query parameters echoed in a response.
+//
+// Record a cassette once against the real API:
+//
+//	rec, err := recorder.New("testdata/audio.json", recorder.ModeRecord,
+//		recorder.WithSecrets(apiSecret, sessionId),
+//	)
+//	client := omnia.NewClient(domainId, apiSecret, sessionId, omnia.WithHTTPClient(rec.Client()))
+//	// ... use client ...
+//	err = rec.Save()
+//
+// And replay it in the tests:
+//
+//	rec, err := recorder.New("testdata/audio.json", recorder.ModeReplay)
+//	client := omnia.NewClient("23", "secret", "42", omnia.WithHTTPClient(rec.Client()))
+//
+// Requests are matched on the HTTP method, the URL path and the normalized query
+// parameters. Interactions with the same request are replayed in the order they were
+// recorded, the last one is repeated once all are used.
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Whether the recorder talks to the real API or replays a cassette.
+type Mode int
+
+const (
+	// Replay the interactions of an existing cassette. Requests without a
+	// matching interaction fail.
+	ModeReplay Mode = iota
+	// Send all requests to the real API and record them.
+	ModeRecord
+)
+
+// Replacement for scrubbed values.
+const scrubbedValue = "[SCRUBBED]"
+
+// Headers which are never recorded.
+var scrubbedHeaders = []string{"X-Request-Token", "X-Request-CID", "Set-Cookie"}
+
+// JSON attributes which are always scrubbed from recorded bodies.
+var defaultScrubbedFields = []string{"secret"}
+
+// Recorder is a [http.RoundTripper] recording or replaying interactions with omnia.
+// It's safe for concurrent use.
+type Recorder struct {
+	mode         Mode
+	path         string
+	transport    http.RoundTripper
+	secrets      []string
+	fields       []string
+	ignoredQuery []string
+
+	mutex    sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// Alters the configuration of a [Recorder].
+type Option func(*Recorder)
+
+// Use the given transport to reach the real API in record mode. Defaults to
+// [http.DefaultTransport].
+func WithTransport(transport http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// Replace the given values wherever they show up in a recorded request query or
+// response (headers and body). Pass the API secret and the session id of the domain.
+func WithSecrets(secrets ...string) Option {
+	return func(r *Recorder) {
+		for _, secret := range secrets {
+			if secret != "" {
+				r.secrets = append(r.secrets, secret)
+			}
+		}
+	}
+}
+
+// Scrub the given JSON attributes (at any depth) from recorded bodies and query
+// parameters of the same name in addition to `secret`.
+func WithScrubbedFields(fields ...string) Option {
+	return func(r *Recorder) {
+		r.fields = append(r.fields, fields...)
+	}
+}
+
+// Ignore the given query parameters when matching requests, e.g. parameters
+// containing timestamps.
+func WithIgnoredQueryParams(params ...string) Option {
+	return func(r *Recorder) {
+		r.ignoredQuery = append(r.ignoredQuery, params...)
+	}
+}
+
+// Returns a new recorder for the cassette at the given path. In replay mode the
+// cassette is loaded immediately.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	rsl := &Recorder{
+		mode:      mode,
+		path:      path,
+		transport: http.DefaultTransport,
+		fields:    append([]string(nil), defaultScrubbedFields...),
+	}
+	for _, opt := range opts {
+		opt(rsl)
+	}
+	if mode == ModeReplay {
+		cassette, err := LoadCassette(path)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't load cassette, %w", err)
+		}
+		rsl.cassette = *cassette
+		rsl.used = make([]bool, len(cassette.Interactions))
+	}
+	return rsl, nil
+}
+
+// Returns a HTTP client using the recorder as transport. Pass it to
+// `omnia.WithHTTPClient`.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Returns a copy of the interactions recorded or loaded so far.
+func (r *Recorder) Cassette() Cassette {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return Cassette{
+		Interactions: append([]Interaction(nil), r.cassette.Interactions...),
+	}
+}
+
+// Writes the recorded interactions to the cassette file. Does nothing in replay
+// mode.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	return r.Cassette().Save(r.path)
+}
+
+// Records or replays a request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  NormalizeQuery(r.scrubQuery(req.URL.Query()), r.ignoredQuery...),
+	}
+	if r.mode == ModeReplay {
+		return r.replay(req, key)
+	}
+	return r.record(req, key)
+}
+
+func (r *Recorder) replay(req *http.Request, key RecordedRequest) (*http.Response, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	last := -1
+	for i, interaction := range r.cassette.Interactions {
+		if interaction.Request != key {
+			continue
+		}
+		last = i
+		if !r.used[i] {
+			r.used[i] = true
+			return interaction.Response.toHttp(req), nil
+		}
+	}
+	if last >= 0 {
+		return r.cassette.Interactions[last].Response.toHttp(req), nil
+	}
+	return nil, fmt.Errorf("no recorded interaction for %s %s?%s in %s", key.Method, key.Path, key.Query, r.path)
+}
+
+func (r *Recorder) record(req *http.Request, key RecordedRequest) (*http.Response, error) {
+	rsp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+	// Values of the request which are secret but might be echoed by omnia.
+	secrets := r.requestSecrets(req)
+	recorded := RecordedResponse{
+		StatusCode: rsp.StatusCode,
+		Header:     r.scrubHeader(rsp.Header, secrets),
+		Body:       r.scrubBody(string(body), secrets),
+	}
+	r.mutex.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request:  key,
+		Response: recorded,
+	})
+	r.mutex.Unlock()
+
+	// The caller gets the original response, only the recording is scrubbed.
+	rsp.Body = io.NopCloser(bytes.NewReader(body))
+	return rsp, nil
+}
+
+// Returns the values of the scrubbed headers and query parameters of the request.
+func (r *Recorder) requestSecrets(req *http.Request) []string {
+	var rsl []string
+	for _, key := range scrubbedHeaders {
+		rsl = append(rsl, req.Header.Values(key)...)
+	}
+	for key, values := range req.URL.Query() {
+		if contains(r.fields, key) {
+			rsl = append(rsl, values...)
+		}
+	}
+	return rsl
+}
+
+func (r *Recorder) scrubString(value string, secrets ...string) string {
+	for _, list := range [][]string{r.secrets, secrets} {
+		for _, secret := range list {
+			if secret != "" {
+				value = strings.ReplaceAll(value, secret, scrubbedValue)
+			}
+		}
+	}
+	return value
+}
+
+// Scrubs the secrets and the scrubbed fields from the query. The query of a replayed
+// request is scrubbed the same way, so it matches the recorded one as long as the
+// recorder is configured alike.
+func (r *Recorder) scrubQuery(query url.Values) url.Values {
+	rsl := url.Values{}
+	for key, values := range query {
+		scrubbed := make([]string, len(values))
+		for i, value := range values {
+			if contains(r.fields, key) {
+				scrubbed[i] = scrubbedValue
+			} else {
+				scrubbed[i] = r.scrubString(value)
+			}
+		}
+		rsl[key] = scrubbed
+	}
+	return rsl
+}
+
+func (r *Recorder) scrubHeader(header http.Header, secrets []string) map[string][]string {
+	rsl := map[string][]string{}
+	for key, values := range header {
+		if contains(scrubbedHeaders, key) {
+			continue
+		}
+		scrubbed := make([]string, len(values))
+		for i, value := range values {
+			scrubbed[i] = r.scrubString(value, secrets...)
+		}
+		rsl[key] = scrubbed
+	}
+	return rsl
+}
+
+func (r *Recorder) scrubBody(body string, secrets []string) string {
+	body = r.scrubString(body, secrets...)
+	var doc interface{}
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return body
+	}
+	rsl, err := json.Marshal(r.scrubValue(doc))
+	if err != nil {
+		return body
+	}
+	return string(rsl)
+}
+
+func (r *Recorder) scrubValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, item := range value {
+			if contains(r.fields, key) {
+				value[key] = scrubbedValue
+			} else {
+				value[key] = r.scrubValue(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = r.scrubValue(item)
+		}
+	}
+	return value
+}
+
+// Builds a HTTP response for the given request.
+func (r RecordedResponse) toHttp(req *http.Request) *http.Response {
+	header := http.Header{}
+	for key, values := range r.Header {
+		header[key] = append([]string(nil), values...)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
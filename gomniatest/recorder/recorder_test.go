@@ -0,0 +1,144 @@
+package recorder_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	omnia "github.com/alex-berlin-tv/gomnia"
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/alex-berlin-tv/gomnia/gomniatest/recorder"
+	"github.com/alex-berlin-tv/gomnia/params"
+)
+
+const (
+	domainId  = "23"
+	apiSecret = "api-secret-value"
+	sessionId = "session-id-value"
+)
+
+// Answers every request with a response leaking the credentials in headers and body.
+func leakingServer(t *testing.T, token, rawQuery *string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*token = r.Header.Get("X-Request-Token")
+		*rawQuery = r.URL.RawQuery
+		w.Header().Set("X-Request-Token", *token)
+		w.Header().Set("X-Request-CID", r.Header.Get("X-Request-CID"))
+		w.Header().Set("X-Echo", apiSecret)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"metadata":{"status":200,"calledWith":%q},"result":{"general":{"ID":72,"title":"Episode"},"hook":{"secret":"hook-secret"},"note":"session %s, token %s"}}`,
+			r.URL.String(), sessionId, *token)
+	}))
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var token, rawQuery string
+	srv := leakingServer(t, &token, &rawQuery)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	query := params.Custom{"secret": "query-secret", "marker": apiSecret}
+
+	rec, err := recorder.New(path, recorder.ModeRecord, recorder.WithSecrets(apiSecret, sessionId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := omnia.NewClient(domainId, apiSecret, sessionId,
+		omnia.WithBaseURL(srv.URL),
+		omnia.WithHTTPClient(rec.Client()),
+	)
+	rsl, err := client.ById(enum.AudioStreamType, 72, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsl.Result.General.Title != "Episode" {
+		t.Fatalf("caller got %q instead of the original response", rsl.Result.General.Title)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leaked := range []string{token, sessionId, apiSecret, "hook-secret", "query-secret", "X-Request-Token", "X-Request-Cid"} {
+		if bytes.Contains(data, []byte(leaked)) {
+			t.Errorf("cassette contains %q:\n%s", leaked, data)
+		}
+	}
+	if !bytes.Contains(data, []byte("[SCRUBBED]")) {
+		t.Errorf("cassette contains no scrubbed values:\n%s", data)
+	}
+
+	rec, err = recorder.New(path, recorder.ModeReplay, recorder.WithSecrets(apiSecret, sessionId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = omnia.NewClient(domainId, apiSecret, sessionId,
+		omnia.WithBaseURL(srv.URL),
+		omnia.WithHTTPClient(rec.Client()),
+	)
+	rsl, err = client.ById(enum.AudioStreamType, 72, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsl.Result.General.Id != 72 {
+		t.Fatalf("replayed item %d, want 72", rsl.Result.General.Id)
+	}
+
+	// The same query in another order matches the recorded interaction.
+	parts := strings.Split(rawQuery, "&")
+	for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+		parts[i], parts[j] = parts[j], parts[i]
+	}
+	reordered := strings.Join(parts, "&")
+	if reordered == rawQuery {
+		t.Fatalf("query %q can't be reordered", rawQuery)
+	}
+	rsp, err := rec.Client().Get(fmt.Sprintf("%s/%s/audio/byid/72?%s", srv.URL, domainId, reordered))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rsp.Body.Close()
+	body, _ := io.ReadAll(rsp.Body)
+	if rsp.StatusCode != http.StatusOK || !bytes.Contains(body, []byte("Episode")) {
+		t.Fatalf("unexpected replay %d: %s", rsp.StatusCode, body)
+	}
+}
+
+func TestReplayWithoutMatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := (recorder.Cassette{}).Save(path); err != nil {
+		t.Fatal(err)
+	}
+	rec, err := recorder.New(path, recorder.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rec.Client().Get("http://example.invalid/23/audio/byid/72"); err == nil {
+		t.Fatal("expected an error for a request without recorded interaction")
+	}
+}
+
+// Replays the example cassette in testdata without any network access.
+func TestReplayCassetteFile(t *testing.T) {
+	rec, err := recorder.New(filepath.Join("testdata", "audio_byid.json"), recorder.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := omnia.NewClient(domainId, "secret", "session", omnia.WithHTTPClient(rec.Client()))
+	rsl, err := client.ById(enum.AudioStreamType, 72, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsl.Result.General.Title != "Episode 72" || rsl.Result.General.IsPicked != enum.YesBool {
+		t.Fatalf("unexpected item %+v", rsl.Result.General)
+	}
+}
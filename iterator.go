@@ -0,0 +1,168 @@
+package gomnia
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/alex-berlin-tv/gomnia/params"
+)
+
+// Operations of the Media API returning a list of items which can be paged through
+// using an [Iterator].
+type ListOperation string
+
+const (
+	// All items, see [Client.All].
+	AllOperation = ListOperation("all")
+	// All items sorted by creation date, see [Client.Latest].
+	LatestOperation = ListOperation("latest")
+	// All picked items, see [Client.Picked].
+	PickedOperation = ListOperation("picked")
+	// All evergreen items, see [Client.Evergreens].
+	EvergreensOperation = ListOperation("evergreens")
+	// All items created for kids, see [Client.ForKids].
+	ForKidsOperation = ListOperation("forkids")
+	// Items matching a query, use [Client.IterateQuery] for this operation.
+	ByQueryOperation = ListOperation("byquery")
+)
+
+// Whether the operation is one of the [ListOperation]s.
+func isListOperation(operation string) bool {
+	switch ListOperation(operation) {
+	case AllOperation, LatestOperation, PickedOperation, EvergreensOperation, ForKidsOperation, ByQueryOperation:
+		return true
+	}
+	return false
+}
+
+// Number of items requested per page.
+const pageSize = 100
+
+// Iterator lazily pages through the result of a listing endpoint. Pages are only
+// fetched when needed, thus only one page is held in memory at any time. Example,
+// process all audio items and stop at the first item without a title:
+//
+//	it := client.Iterate(ctx, enum.AudioStreamType, omnia.AllOperation, nil)
+//	for it.Next() {
+//		item := it.Item()
+//		if item.General.Title == "" {
+//			break
+//		}
+//	}
+//	if err := it.Err(); err != nil {
+//		log.Error(err)
+//	}
+//
+// Items which show up on more than one page (because items were added while
+// iterating) are only returned once. An Iterator is not safe for concurrent use.
+type Iterator struct {
+	ctx        context.Context
+	client     Client
+	streamType enum.StreamType
+	operation  ListOperation
+	args       []string
+	parameters params.QueryParameters
+	page       MediaResult
+	index      int
+	pager      *pager
+	paging     *ResponsePaging
+	done       bool
+	err        error
+}
+
+// Returns an iterator over all items of a listing operation. No request is sent
+// until [Iterator.Next] is called for the first time. A limit given in the
+// parameters is used as page size and a start value as offset of the first page,
+// the offsets of the following pages are controlled by the iterator.
+func (o Client) Iterate(ctx context.Context, streamType enum.StreamType, operation ListOperation, parameters params.QueryParameters) *Iterator {
+	return &Iterator{
+		ctx:        ctx,
+		client:     o,
+		streamType: streamType,
+		operation:  operation,
+		parameters: parameters,
+		index:      -1,
+		pager:      newPager(),
+	}
+}
+
+// Returns an iterator over all items matching the given query. See [Client.Iterate]
+// and [Client.ByQuery] for more information.
+func (o Client) IterateQuery(ctx context.Context, streamType enum.StreamType, query string, parameters params.QueryParameters) *Iterator {
+	it := o.Iterate(ctx, streamType, ByQueryOperation, parameters)
+	it.args = []string{url.PathEscape(query)}
+	return it
+}
+
+// Advances the iterator to the next item, fetching the next page if necessary.
+// Returns false if there are no more items or an error occurred. Use [Iterator.Err]
+// to distinguish between these cases.
+func (it *Iterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.index+1 < len(it.page) {
+		it.index++
+		return true
+	}
+	if it.done {
+		return false
+	}
+	for {
+		if err := it.fetch(); err != nil {
+			it.err = err
+			return false
+		}
+		if len(it.page) > 0 {
+			it.index = 0
+			return true
+		}
+		// A page might consist only of already seen items.
+		if it.done {
+			return false
+		}
+	}
+}
+
+// Returns the current item. Only valid after a call to [Iterator.Next] returned
+// true.
+func (it *Iterator) Item() MediaResultItem {
+	if it.index < 0 || it.index >= len(it.page) {
+		return MediaResultItem{}
+	}
+	return it.page[it.index]
+}
+
+// Returns the error which stopped the iteration, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Reports if the total number of items changed during the iteration. Items are
+// never returned twice but might be missed if they moved to an already fetched
+// page. Returns nil if the total number didn't change.
+func (it *Iterator) ResultCountChange() *ResultCountChangedError {
+	return it.pager.changed
+}
+
+// Returns the paging information of the most recently fetched page. Nil if no page
+// was fetched yet.
+func (it *Iterator) Paging() *ResponsePaging {
+	return it.paging
+}
+
+// Fetches the next page.
+func (it *Iterator) fetch() error {
+	if err := it.ctx.Err(); err != nil {
+		return err
+	}
+	rsp, err := it.client.fetchPage(it.ctx, it.streamType, it.operation, it.args, it.parameters, it.pager.next)
+	if err != nil {
+		return err
+	}
+	it.page = it.pager.add(rsp)
+	it.paging = rsp.Paging
+	it.done = it.pager.done(rsp)
+	return nil
+}
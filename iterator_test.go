@@ -0,0 +1,138 @@
+package gomnia_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	omnia "github.com/alex-berlin-tv/gomnia"
+	"github.com/alex-berlin-tv/gomnia/enum"
+)
+
+// Returns the IDs of the remaining items of the iterator.
+func drain(it *omnia.Iterator) []int {
+	var rsl []int
+	for it.Next() {
+		rsl = append(rsl, it.Item().General.Id)
+	}
+	return rsl
+}
+
+func TestIterator(t *testing.T) {
+	l, client := newListing(t, func(pass, start int) ([]int, int) {
+		return window(start, 5), 5
+	})
+	it := client.Iterate(context.Background(), enum.AudioStreamType, omnia.AllOperation, nil)
+	if it.Paging() != nil || len(l.requestedStarts()) != 0 {
+		t.Fatal("a page was fetched before calling Next")
+	}
+	if got, want := drain(it), []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got items %v, want %v", got, want)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if it.Next() {
+		t.Fatal("Next returned true after the last item")
+	}
+	if got, want := l.requestedStarts(), []int{0, 2, 4}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("requested offsets %v, want %v", got, want)
+	}
+	if it.Paging() == nil || it.Paging().Start != 4 || it.ResultCountChange() != nil {
+		t.Fatalf("unexpected paging %+v", it.Paging())
+	}
+}
+
+func TestIteratorEarlyStop(t *testing.T) {
+	l, client := newListing(t, func(pass, start int) ([]int, int) {
+		return window(start, 5), 5
+	})
+	it := client.Iterate(context.Background(), enum.AudioStreamType, omnia.AllOperation, nil)
+	for i := 0; i < 3; i++ {
+		if !it.Next() {
+			t.Fatalf("iteration stopped after %d items: %v", i, it.Err())
+		}
+	}
+	if got, want := l.requestedStarts(), []int{0, 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("requested offsets %v, want only the pages needed %v", got, want)
+	}
+}
+
+func TestIteratorSeenPage(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+		want  []int
+	}{
+		// The page at 2 repeats the first page, iterating continues with the next one.
+		{"within the listing", 6, []int{1, 2, 5, 6}},
+		// The repeated page is the last one.
+		{"last page", 4, []int{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, client := newListing(t, func(pass, start int) ([]int, int) {
+				if start == 2 {
+					return window(0, tt.count), tt.count
+				}
+				return window(start, tt.count), tt.count
+			})
+			it := client.Iterate(context.Background(), enum.AudioStreamType, omnia.AllOperation, nil)
+			if got := drain(it); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got items %v, want %v", got, tt.want)
+			}
+			if err := it.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if starts := l.requestedStarts(); len(starts) != tt.count/2 {
+				t.Fatalf("requested offsets %v, want %d pages", starts, tt.count/2)
+			}
+		})
+	}
+}
+
+func TestIteratorError(t *testing.T) {
+	l, client := newListing(t, func(pass, start int) ([]int, int) {
+		if start == 2 {
+			return nil, -1
+		}
+		return window(start, 5), 5
+	})
+	it := client.Iterate(context.Background(), enum.AudioStreamType, omnia.AllOperation, nil)
+	if got, want := drain(it), []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got items %v before the error, want %v", got, want)
+	}
+	if !errors.Is(it.Err(), omnia.ErrNotFound) {
+		t.Fatalf("got error %v, want ErrNotFound", it.Err())
+	}
+	if it.Next() {
+		t.Fatal("Next returned true after an error")
+	}
+	if got, want := l.requestedStarts(), []int{0, 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("requested offsets %v, want %v", got, want)
+	}
+}
+
+func TestIteratorCancel(t *testing.T) {
+	l, client := newListing(t, func(pass, start int) ([]int, int) {
+		return window(start, 5), 5
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := client.Iterate(ctx, enum.AudioStreamType, omnia.AllOperation, nil)
+	if !it.Next() {
+		t.Fatal(it.Err())
+	}
+	cancel()
+	// The items of the fetched page are still returned.
+	if got, want := drain(it), []int{2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got items %v after canceling, want %v", got, want)
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Fatalf("got error %v, want context.Canceled", it.Err())
+	}
+	if got, want := l.requestedStarts(), []int{0}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("requested offsets %v, want %v", got, want)
+	}
+}
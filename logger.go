@@ -0,0 +1,91 @@
+package gomnia
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// Structured data attached to a log message.
+type Fields map[string]interface{}
+
+// Level of a log message.
+type Level int
+
+const (
+	// Very verbose information like complete response bodies.
+	TraceLevel Level = iota
+	// Information useful for debugging like the sent requests.
+	DebugLevel
+)
+
+// Logger receives the log output of a [Client]. The library only logs on the debug
+// and trace level, errors are always returned to the caller. Set a logger using
+// [WithLogger]. If no logger is set, the standard logger of logrus is used.
+type Logger interface {
+	// Logs very verbose information like complete response bodies.
+	Trace(msg string, fields Fields)
+	// Logs information useful for debugging like the sent requests.
+	Debug(msg string, fields Fields)
+	// Reports whether messages of the given level are logged. The client doesn't
+	// build messages which would be discarded anyway.
+	Enabled(level Level) bool
+}
+
+// Send the log output of the client to the given logger. Use [NopLogger] to disable
+// logging altogether.
+func WithLogger(logger Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// Returns the logger of the client. Unless disabled, secrets are redacted from
+// all messages.
+func (o Client) log() Logger {
+	logger := o.logger
+	if logger == nil {
+		logger = defaultLogger
+	}
+	// The library logs only at the debug and trace level, there is nothing to
+	// redact if both are disabled.
+	if o.noRedaction || !(logger.Enabled(DebugLevel) || logger.Enabled(TraceLevel)) {
+		return logger
+	}
+	return o.redactingLogger(logger)
+}
+
+var defaultLogger = NewLogrusLogger(logrus.StandardLogger())
+
+type logrusLogger struct {
+	logger *logrus.Logger
+}
+
+// Returns a [Logger] writing to the given logrus logger.
+func NewLogrusLogger(logger *logrus.Logger) Logger {
+	return logrusLogger{logger: logger}
+}
+
+func (l logrusLogger) Trace(msg string, fields Fields) {
+	l.logger.WithFields(logrus.Fields(fields)).Trace(msg)
+}
+
+func (l logrusLogger) Debug(msg string, fields Fields) {
+	l.logger.WithFields(logrus.Fields(fields)).Debug(msg)
+}
+
+func (l logrusLogger) Enabled(level Level) bool {
+	if level == TraceLevel {
+		return l.logger.IsLevelEnabled(logrus.TraceLevel)
+	}
+	return l.logger.IsLevelEnabled(logrus.DebugLevel)
+}
+
+type nopLogger struct{}
+
+// A [Logger] discarding all messages.
+var NopLogger Logger = nopLogger{}
+
+func (nopLogger) Trace(msg string, fields Fields) {}
+
+func (nopLogger) Debug(msg string, fields Fields) {}
+
+func (nopLogger) Enabled(level Level) bool { return false }
@@ -0,0 +1,54 @@
+//go:build go1.21
+
+package gomnia
+
+import (
+	"context"
+	"log/slog"
+)
+
+// The level used for trace messages when logging with [log/slog], which doesn't
+// define a trace level.
+const SlogLevelTrace = slog.LevelDebug - 4
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// Returns a [Logger] writing to the given [log/slog] logger. Debug messages are
+// logged with [slog.LevelDebug], trace messages with [SlogLevelTrace].
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return slogLogger{logger: logger}
+}
+
+func (l slogLogger) Trace(msg string, fields Fields) {
+	l.log(SlogLevelTrace, msg, fields)
+}
+
+func (l slogLogger) Debug(msg string, fields Fields) {
+	l.log(slog.LevelDebug, msg, fields)
+}
+
+func (l slogLogger) Enabled(level Level) bool {
+	return l.logger.Enabled(context.Background(), slogLevel(level))
+}
+
+// Maps a [Level] to the corresponding [slog.Level].
+func slogLevel(level Level) slog.Level {
+	if level == TraceLevel {
+		return SlogLevelTrace
+	}
+	return slog.LevelDebug
+}
+
+func (l slogLogger) log(level slog.Level, msg string, fields Fields) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := make([]slog.Attr, 0, len(fields))
+	for key, value := range fields {
+		attrs = append(attrs, slog.Any(key, value))
+	}
+	l.logger.LogAttrs(ctx, level, msg, attrs...)
+}
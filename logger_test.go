@@ -0,0 +1,111 @@
+package gomnia_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	omnia "github.com/alex-berlin-tv/gomnia"
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/alex-berlin-tv/gomnia/gomniatest/fakeserver"
+	"github.com/alex-berlin-tv/gomnia/params"
+)
+
+// Records all messages of the enabled levels and counts the calls of the disabled
+// ones.
+type recordingLogger struct {
+	mutex    sync.Mutex
+	enabled  map[omnia.Level]bool
+	messages map[omnia.Level][]string
+	disabled map[omnia.Level]int
+}
+
+func newRecordingLogger(enabled ...omnia.Level) *recordingLogger {
+	rsl := &recordingLogger{
+		enabled:  map[omnia.Level]bool{},
+		messages: map[omnia.Level][]string{},
+		disabled: map[omnia.Level]int{},
+	}
+	for _, level := range enabled {
+		rsl.enabled[level] = true
+	}
+	return rsl
+}
+
+func (l *recordingLogger) log(level omnia.Level, msg string, fields omnia.Fields) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if !l.enabled[level] {
+		l.disabled[level]++
+		return
+	}
+	l.messages[level] = append(l.messages[level], fmt.Sprintf("%s %v", msg, fields))
+}
+
+func (l *recordingLogger) Trace(msg string, fields omnia.Fields) {
+	l.log(omnia.TraceLevel, msg, fields)
+}
+
+func (l *recordingLogger) Debug(msg string, fields omnia.Fields) {
+	l.log(omnia.DebugLevel, msg, fields)
+}
+
+func (l *recordingLogger) Enabled(level omnia.Level) bool { return l.enabled[level] }
+
+func TestLoggerLevels(t *testing.T) {
+	srv := fakeserver.New(fakeserver.WithItems(enum.AudioStreamType, fakeserver.Item{}))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		enabled []omnia.Level
+	}{
+		{"nothing", nil},
+		{"debug", []omnia.Level{omnia.DebugLevel}},
+		{"trace and debug", []omnia.Level{omnia.TraceLevel, omnia.DebugLevel}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := newRecordingLogger(tt.enabled...)
+			client := srv.Client(omnia.WithLogger(logger), omnia.WithMiddleware(omnia.LoggingMiddleware(logger)))
+			if _, err := client.Publish(enum.AudioStreamType, 1); err != nil {
+				t.Fatal(err)
+			}
+			for level, n := range logger.disabled {
+				t.Errorf("%d messages on level %d were built although the level is disabled", n, level)
+			}
+			for _, level := range []omnia.Level{omnia.TraceLevel, omnia.DebugLevel} {
+				if got := len(logger.messages[level]) > 0; got != logger.enabled[level] {
+					t.Errorf("messages logged on level %d: %v, want %v", level, got, logger.enabled[level])
+				}
+			}
+		})
+	}
+}
+
+func TestLoggerRedaction(t *testing.T) {
+	const apiSecret, sessionId, password = "api-secret-value", "session-id-value", "link-password-value"
+	srv := fakeserver.New(
+		fakeserver.WithCredentials(fakeserver.DefaultDomainId, apiSecret, sessionId),
+		fakeserver.WithItems(enum.AudioStreamType, fakeserver.Item{}),
+	)
+	defer srv.Close()
+
+	logger := newRecordingLogger(omnia.TraceLevel, omnia.DebugLevel)
+	client := srv.Client(omnia.WithLogger(logger))
+	if _, err := client.Publish(enum.AudioStreamType, 1); err != nil {
+		t.Fatal(err)
+	}
+	link := params.UploadLink{Title: "Link", SelectedStreamtypes: "video", Language: "de", Password: password}
+	if _, err := client.AddUploadLink(link); err != nil {
+		t.Fatal(err)
+	}
+	for level, messages := range logger.messages {
+		for _, msg := range messages {
+			if strings.Contains(msg, apiSecret) || strings.Contains(msg, sessionId) || strings.Contains(msg, password) {
+				t.Errorf("message on level %d leaks a secret: %s", level, msg)
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package gomnia
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alex-berlin-tv/gomnia/enum"
+)
+
+// Observation of a single API call passed to [Metrics].
+type CallObservation struct {
+	// The class of the called API.
+	Class ApiClass
+	// Streamtype of the call.
+	StreamType enum.StreamType
+	// The called operation (e.g. `byid` or `update`).
+	Operation string
+	// HTTP method of the call.
+	Method string
+	// Status of the call as reported by omnia in the response metadata or, if
+	// not available, the HTTP status. Zero if no response was received.
+	Status int
+	// The error of the call, nil if the call succeeded.
+	Err error
+	// Wall time of the call including retries and waiting for rate limiters.
+	Latency time.Duration
+	// Time omnia needed to process the request as reported in
+	// [ResponseMetadata.ProcessingTime].
+	ProcessingTime time.Duration
+	// Whether omnia answered from its cache.
+	FromCache bool
+}
+
+// Receives an observation for each API call of a [Client]. Implementations have to
+// be safe for concurrent use. The package provides an adapter exposing the metrics
+// in the Prometheus text format ([PrometheusMetrics]) and one publishing them using
+// the expvar package ([ExpvarMetrics]).
+type Metrics interface {
+	ObserveCall(observation CallObservation)
+}
+
+// Report all calls of the client to the given metrics. Multiple metrics can be set
+// by using this option more than once.
+func WithMetrics(metrics Metrics) ClientOption {
+	return WithMiddleware(MetricsMiddleware(metrics))
+}
+
+// Returns a middleware reporting each call to the given metrics.
+func MetricsMiddleware(metrics Metrics) Middleware {
+	return func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, req *Request) (*RawResponse, error) {
+			start := time.Now()
+			rsp, err := next(ctx, req)
+			observation := CallObservation{
+				Class:      req.Class,
+				StreamType: req.StreamType,
+				Operation:  req.Operation,
+				Method:     req.Method,
+				Err:        err,
+				Latency:    time.Since(start),
+			}
+			if rsp != nil {
+				metadata := rsp.metadata()
+				observation.Status = metadata.Status
+				if observation.Status == 0 {
+					observation.Status = rsp.StatusCode
+				}
+				observation.ProcessingTime = time.Duration(metadata.ProcessingTime * float64(time.Second))
+				observation.FromCache = metadata.FromCache != nil && *metadata.FromCache == 1
+				if err == nil && (observation.Status < 200 || observation.Status > 299) {
+					observation.Err = newApiError(rsp.StatusCode, metadata, req.Method, req.Endpoint, req.Operation, req.StreamType)
+				}
+			} else {
+				var apiErr *APIError
+				if errors.As(err, &apiErr) {
+					observation.Status = apiErr.StatusCode()
+				}
+			}
+			metrics.ObserveCall(observation)
+			return rsp, err
+		}
+	}
+}
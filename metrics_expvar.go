@@ -0,0 +1,48 @@
+package gomnia
+
+import (
+	"expvar"
+	"fmt"
+	"strconv"
+)
+
+// ExpvarMetrics publishes the [CallObservation]s of one or more clients using the
+// expvar package. All values are stored in a single [expvar.Map], the keys have the
+// form `<class>.<streamtype>.<operation>.<value>`. The following values are kept:
+//
+//   - requests: number of calls.
+//   - errors: number of failed calls.
+//   - status_<code>: number of calls per status.
+//   - cache_hits: number of responses served from omnia's cache.
+//   - latency_ms: accumulated wall latency in milliseconds.
+//   - processing_ms: accumulated processing time reported by omnia in milliseconds.
+type ExpvarMetrics struct {
+	values *expvar.Map
+}
+
+// Publishes a new map with the given name. As with all expvar variables the name
+// has to be unique within the process, otherwise this function panics.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	return &ExpvarMetrics{
+		values: expvar.NewMap(name),
+	}
+}
+
+// Returns the underlying expvar map.
+func (m *ExpvarMetrics) Map() *expvar.Map {
+	return m.values
+}
+
+func (m *ExpvarMetrics) ObserveCall(observation CallObservation) {
+	prefix := fmt.Sprintf("%s.%s.%s.", observation.Class, observation.StreamType, observation.Operation)
+	m.values.Add(prefix+"requests", 1)
+	if observation.Err != nil {
+		m.values.Add(prefix+"errors", 1)
+	}
+	m.values.Add(prefix+"status_"+strconv.Itoa(observation.Status), 1)
+	if observation.FromCache {
+		m.values.Add(prefix+"cache_hits", 1)
+	}
+	m.values.AddFloat(prefix+"latency_ms", float64(observation.Latency.Microseconds())/1000)
+	m.values.AddFloat(prefix+"processing_ms", float64(observation.ProcessingTime.Microseconds())/1000)
+}
@@ -0,0 +1,180 @@
+package gomnia
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Default histogram buckets (in seconds) used by [PrometheusMetrics].
+var DefaultPrometheusBuckets = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// PrometheusMetrics collects the [CallObservation]s of one or more clients and
+// exposes them in the Prometheus text exposition format. It implements
+// [http.Handler] so it can be mounted directly as a scrape endpoint:
+//
+//	metrics := omnia.NewPrometheusMetrics(nil)
+//	client := omnia.NewClient("23", "Secret", "42", omnia.WithMetrics(metrics))
+//	http.Handle("/metrics", metrics)
+//
+// The following metrics are exposed, all labeled with the API class, stream type
+// and operation:
+//
+//   - gomnia_requests_total: counter of calls, additionally labeled with the status.
+//   - gomnia_request_errors_total: counter of failed calls, additionally labeled
+//     with the status.
+//   - gomnia_cache_hits_total: counter of responses served from omnia's cache.
+//   - gomnia_request_duration_seconds: histogram of the wall latency.
+//   - gomnia_processing_duration_seconds: histogram of omnia's processing time.
+type PrometheusMetrics struct {
+	mutex      sync.Mutex
+	buckets    []float64
+	requests   map[promKey]uint64
+	errors     map[promKey]uint64
+	cacheHits  map[promKey]uint64
+	latency    map[promKey]*promHistogram
+	processing map[promKey]*promHistogram
+}
+
+// Returns a new collector using the given histogram buckets (upper bounds in seconds).
+// If no buckets are given, [DefaultPrometheusBuckets] are used.
+func NewPrometheusMetrics(buckets []float64) *PrometheusMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultPrometheusBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &PrometheusMetrics{
+		buckets:    sorted,
+		requests:   map[promKey]uint64{},
+		errors:     map[promKey]uint64{},
+		cacheHits:  map[promKey]uint64{},
+		latency:    map[promKey]*promHistogram{},
+		processing: map[promKey]*promHistogram{},
+	}
+}
+
+// Labels of a metric.
+type promKey struct {
+	class      string
+	streamType string
+	operation  string
+	status     string
+}
+
+func (k promKey) labels() string {
+	parts := []string{
+		fmt.Sprintf("class=%q", k.class),
+		fmt.Sprintf("operation=%q", k.operation),
+	}
+	if k.status != "" {
+		parts = append(parts, fmt.Sprintf("status=%q", k.status))
+	}
+	parts = append(parts, fmt.Sprintf("stream_type=%q", k.streamType))
+	return strings.Join(parts, ",")
+}
+
+type promHistogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func (h *promHistogram) observe(buckets []float64, value float64) {
+	for i, bound := range buckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+func (m *PrometheusMetrics) ObserveCall(observation CallObservation) {
+	key := promKey{
+		class:      string(observation.Class),
+		streamType: string(observation.StreamType),
+		operation:  observation.Operation,
+	}
+	statusKey := key
+	statusKey.status = strconv.Itoa(observation.Status)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.requests[statusKey]++
+	if observation.Err != nil {
+		m.errors[statusKey]++
+	}
+	if observation.FromCache {
+		m.cacheHits[key]++
+	}
+	m.histogram(m.latency, key).observe(m.buckets, observation.Latency.Seconds())
+	if observation.ProcessingTime > 0 {
+		m.histogram(m.processing, key).observe(m.buckets, observation.ProcessingTime.Seconds())
+	}
+}
+
+func (m *PrometheusMetrics) histogram(histograms map[promKey]*promHistogram, key promKey) *promHistogram {
+	rsl, ok := histograms[key]
+	if !ok {
+		rsl = &promHistogram{counts: make([]uint64, len(m.buckets))}
+		histograms[key] = rsl
+	}
+	return rsl
+}
+
+// Writes all metrics in the Prometheus text exposition format to the writer.
+func (m *PrometheusMetrics) WriteTo(w io.Writer) (int64, error) {
+	var builder strings.Builder
+	m.mutex.Lock()
+	writePromCounter(&builder, "gomnia_requests_total", "Number of calls to the omnia API.", m.requests)
+	writePromCounter(&builder, "gomnia_request_errors_total", "Number of failed calls to the omnia API.", m.errors)
+	writePromCounter(&builder, "gomnia_cache_hits_total", "Number of responses served from the omnia cache.", m.cacheHits)
+	m.writeHistogram(&builder, "gomnia_request_duration_seconds", "Wall latency of calls to the omnia API.", m.latency)
+	m.writeHistogram(&builder, "gomnia_processing_duration_seconds", "Processing time reported by omnia.", m.processing)
+	m.mutex.Unlock()
+	n, err := io.WriteString(w, builder.String())
+	return int64(n), err
+}
+
+// Serves the metrics in the Prometheus text exposition format.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w)
+}
+
+func sortedPromKeys[T any](values map[promKey]T) []promKey {
+	rsl := make([]promKey, 0, len(values))
+	for key := range values {
+		rsl = append(rsl, key)
+	}
+	sort.Slice(rsl, func(i, j int) bool {
+		return rsl[i].labels() < rsl[j].labels()
+	})
+	return rsl
+}
+
+func writePromCounter(w *strings.Builder, name, help string, values map[promKey]uint64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	for _, key := range sortedPromKeys(values) {
+		fmt.Fprintf(w, "%s{%s} %d\n", name, key.labels(), values[key])
+	}
+}
+
+func (m *PrometheusMetrics) writeHistogram(w *strings.Builder, name, help string, values map[promKey]*promHistogram) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	for _, key := range sortedPromKeys(values) {
+		histogram := values[key]
+		labels := key.labels()
+		for i, bound := range m.buckets {
+			fmt.Fprintf(w, "%s_bucket{%s,le=%q} %d\n", name, labels, strconv.FormatFloat(bound, 'g', -1, 64), histogram.counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, histogram.count)
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", name, labels, strconv.FormatFloat(histogram.sum, 'g', -1, 64))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, labels, histogram.count)
+	}
+}
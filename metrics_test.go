@@ -0,0 +1,175 @@
+package gomnia_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	omnia "github.com/alex-berlin-tv/gomnia"
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/alex-berlin-tv/gomnia/gomniatest/fakeserver"
+)
+
+// Matches the values of the wall latency histogram which depend on the timing of
+// the test.
+var latencyValue = regexp.MustCompile(`(gomnia_request_duration_seconds_(?:bucket\{[^}]*le="0.001"\}|sum\{[^}]*\})) \S+`)
+
+func TestPrometheusMetrics(t *testing.T) {
+	metrics := omnia.NewPrometheusMetrics([]float64{0.001, 3600})
+	srv := fakeserver.New(fakeserver.WithItems(enum.AudioStreamType, titled("Interview")...))
+	t.Cleanup(srv.Close)
+	client := srv.Client(omnia.WithMetrics(metrics))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.ById(enum.AudioStreamType, 1, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := client.ById(enum.AudioStreamType, 2, nil); !errors.Is(err, omnia.ErrNotFound) {
+		t.Fatalf("got error %v, want ErrNotFound", err)
+	}
+	if _, err := client.Publish(enum.AudioStreamType, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	var builder strings.Builder
+	if _, err := metrics.WriteTo(&builder); err != nil {
+		t.Fatal(err)
+	}
+	got := latencyValue.ReplaceAllString(builder.String(), "$1 X")
+	want := `# HELP gomnia_requests_total Number of calls to the omnia API.
+# TYPE gomnia_requests_total counter
+gomnia_requests_total{class="management",operation="publish",status="200",stream_type="audio"} 1
+gomnia_requests_total{class="media",operation="byid",status="200",stream_type="audio"} 2
+gomnia_requests_total{class="media",operation="byid",status="404",stream_type="audio"} 1
+# HELP gomnia_request_errors_total Number of failed calls to the omnia API.
+# TYPE gomnia_request_errors_total counter
+gomnia_request_errors_total{class="media",operation="byid",status="404",stream_type="audio"} 1
+# HELP gomnia_cache_hits_total Number of responses served from the omnia cache.
+# TYPE gomnia_cache_hits_total counter
+# HELP gomnia_request_duration_seconds Wall latency of calls to the omnia API.
+# TYPE gomnia_request_duration_seconds histogram
+gomnia_request_duration_seconds_bucket{class="management",operation="publish",stream_type="audio",le="0.001"} X
+gomnia_request_duration_seconds_bucket{class="management",operation="publish",stream_type="audio",le="3600"} 1
+gomnia_request_duration_seconds_bucket{class="management",operation="publish",stream_type="audio",le="+Inf"} 1
+gomnia_request_duration_seconds_sum{class="management",operation="publish",stream_type="audio"} X
+gomnia_request_duration_seconds_count{class="management",operation="publish",stream_type="audio"} 1
+gomnia_request_duration_seconds_bucket{class="media",operation="byid",stream_type="audio",le="0.001"} X
+gomnia_request_duration_seconds_bucket{class="media",operation="byid",stream_type="audio",le="3600"} 3
+gomnia_request_duration_seconds_bucket{class="media",operation="byid",stream_type="audio",le="+Inf"} 3
+gomnia_request_duration_seconds_sum{class="media",operation="byid",stream_type="audio"} X
+gomnia_request_duration_seconds_count{class="media",operation="byid",stream_type="audio"} 3
+# HELP gomnia_processing_duration_seconds Processing time reported by omnia.
+# TYPE gomnia_processing_duration_seconds histogram
+gomnia_processing_duration_seconds_bucket{class="management",operation="publish",stream_type="audio",le="0.001"} 1
+gomnia_processing_duration_seconds_bucket{class="management",operation="publish",stream_type="audio",le="3600"} 1
+gomnia_processing_duration_seconds_bucket{class="management",operation="publish",stream_type="audio",le="+Inf"} 1
+gomnia_processing_duration_seconds_sum{class="management",operation="publish",stream_type="audio"} 0.001
+gomnia_processing_duration_seconds_count{class="management",operation="publish",stream_type="audio"} 1
+gomnia_processing_duration_seconds_bucket{class="media",operation="byid",stream_type="audio",le="0.001"} 3
+gomnia_processing_duration_seconds_bucket{class="media",operation="byid",stream_type="audio",le="3600"} 3
+gomnia_processing_duration_seconds_bucket{class="media",operation="byid",stream_type="audio",le="+Inf"} 3
+gomnia_processing_duration_seconds_sum{class="media",operation="byid",stream_type="audio"} 0.003
+gomnia_processing_duration_seconds_count{class="media",operation="byid",stream_type="audio"} 3
+`
+	if got != want {
+		t.Fatalf("got metrics\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPrometheusHistogram(t *testing.T) {
+	metrics := omnia.NewPrometheusMetrics([]float64{1, 0.1, 0.01})
+	for _, latency := range []time.Duration{5 * time.Millisecond, 20 * time.Millisecond, 2 * time.Second} {
+		metrics.ObserveCall(omnia.CallObservation{
+			Class:      omnia.MediaApiClass,
+			StreamType: enum.VideoStreamType,
+			Operation:  "say \"hi\"\n",
+			Latency:    latency,
+			FromCache:  true,
+		})
+	}
+
+	var builder strings.Builder
+	if _, err := metrics.WriteTo(&builder); err != nil {
+		t.Fatal(err)
+	}
+	labels := `class="media",operation="say \"hi\"\n",stream_type="videos"`
+	for _, line := range []string{
+		// Calls without a response are counted with the status 0.
+		`gomnia_requests_total{class="media",operation="say \"hi\"\n",status="0",stream_type="videos"} 3`,
+		fmt.Sprintf(`gomnia_cache_hits_total{%s} 3`, labels),
+		// The buckets are sorted and cumulative.
+		fmt.Sprintf(`gomnia_request_duration_seconds_bucket{%s,le="0.01"} 1`, labels),
+		fmt.Sprintf(`gomnia_request_duration_seconds_bucket{%s,le="0.1"} 2`, labels),
+		fmt.Sprintf(`gomnia_request_duration_seconds_bucket{%s,le="1"} 2`, labels),
+		fmt.Sprintf(`gomnia_request_duration_seconds_bucket{%s,le="+Inf"} 3`, labels),
+		fmt.Sprintf(`gomnia_request_duration_seconds_sum{%s} 2.025`, labels),
+		fmt.Sprintf(`gomnia_request_duration_seconds_count{%s} 3`, labels),
+	} {
+		if !strings.Contains(builder.String(), line+"\n") {
+			t.Errorf("missing line %s in\n%s", line, builder.String())
+		}
+	}
+	if strings.Contains(builder.String(), "gomnia_processing_duration_seconds_bucket") {
+		t.Errorf("calls without processing time are part of the histogram\n%s", builder.String())
+	}
+}
+
+// Records all observations.
+type recordingMetrics struct {
+	mutex        sync.Mutex
+	observations []omnia.CallObservation
+}
+
+func (m *recordingMetrics) ObserveCall(observation omnia.CallObservation) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.observations = append(m.observations, observation)
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	// omnia reports the failure only within the metadata of a HTTP 200 response.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.Contains(r.URL.Path, "/byid/2") {
+			fmt.Fprint(w, `{"metadata":{"status":404,"errorhint":"not found","processingtime":0.5}}`)
+			return
+		}
+		fmt.Fprint(w, `{"metadata":{"status":200,"processingtime":0.25,"fromcache":1},"result":{"general":{"ID":1}}}`)
+	}))
+	t.Cleanup(srv.Close)
+	metrics := &recordingMetrics{}
+	client := omnia.NewClient("1", "secret", "session", omnia.WithBaseURL(srv.URL), omnia.WithMetrics(metrics))
+
+	if _, err := client.ById(enum.AudioStreamType, 1, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ById(enum.AudioStreamType, 2, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(metrics.observations) != 2 {
+		t.Fatalf("got %d observations, want 2", len(metrics.observations))
+	}
+
+	ok := metrics.observations[0]
+	if ok.Err != nil || ok.Status != http.StatusOK || !ok.FromCache || ok.ProcessingTime != 250*time.Millisecond {
+		t.Errorf("unexpected observation of the successful call %+v", ok)
+	}
+	if ok.Class != omnia.MediaApiClass || ok.StreamType != enum.AudioStreamType || ok.Operation != "byid" || ok.Method != http.MethodGet || ok.Latency <= 0 {
+		t.Errorf("unexpected observation of the successful call %+v", ok)
+	}
+	failed := metrics.observations[1]
+	var apiErr *omnia.APIError
+	if !errors.As(failed.Err, &apiErr) || !errors.Is(failed.Err, omnia.ErrNotFound) {
+		t.Errorf("got observed error %v, want an APIError matching ErrNotFound", failed.Err)
+	}
+	if failed.Status != http.StatusNotFound || failed.FromCache || failed.ProcessingTime != 500*time.Millisecond {
+		t.Errorf("unexpected observation of the failed call %+v", failed)
+	}
+}
@@ -0,0 +1,141 @@
+package gomnia
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/alex-berlin-tv/gomnia/params"
+)
+
+// A request to the omnia API as it's passed through the [Middleware] chain. All calls
+// to the Media, Management, Domain and System API are represented by a Request.
+// Middlewares may alter the URL and the header before passing the request on.
+type Request struct {
+	// HTTP method in upper case.
+	Method string
+	// The complete URL including the query parameters.
+	URL string
+	// The called URL without the query parameters.
+	Endpoint string
+	// Header of the request, contains the authentication headers of omnia.
+	Header http.Header
+	// Streamtype of the call.
+	StreamType enum.StreamType
+	// The called operation (e.g. `byid` or `update`).
+	Operation string
+	// The class of the called API.
+	Class ApiClass
+	// Arguments of the call which are part of the URL path (e.g. the item id).
+	Args []string
+	// The parameters given by the caller. Might be nil.
+	Params params.QueryParameters
+	// Raw body of the request. Only set for uploads, nil otherwise.
+	Body []byte
+}
+
+// The raw response to a [Request] with the complete body.
+type RawResponse struct {
+	// Status code of the HTTP response.
+	StatusCode int
+	// Header of the HTTP response.
+	Header http.Header
+	// The complete response body.
+	Body []byte
+}
+
+// Whether the HTTP status states a success.
+func (r RawResponse) successful() bool {
+	return r.StatusCode >= 200 && r.StatusCode <= 299
+}
+
+// Returns the metadata of the response body. Returns the zero value if the body
+// doesn't contain a valid response object.
+func (r RawResponse) metadata() ResponseMetadata {
+	var envelope struct {
+		Metadata ResponseMetadata `json:"metadata"`
+	}
+	json.Unmarshal(r.Body, &envelope)
+	return envelope.Metadata
+}
+
+// Sends a request to omnia and returns the raw response.
+type RoundTrip func(ctx context.Context, req *Request) (*RawResponse, error)
+
+// Middleware wraps a [RoundTrip] in order to observe or modify requests and responses.
+// A middleware is called once per API call, retries and rate limiting happen further
+// down the chain. Example, add a correlation id to all requests:
+//
+//	correlation := func(next omnia.RoundTrip) omnia.RoundTrip {
+//		return func(ctx context.Context, req *omnia.Request) (*omnia.RawResponse, error) {
+//			req.Header.Set("X-Correlation-ID", correlationId(ctx))
+//			return next(ctx, req)
+//		}
+//	}
+//	client := omnia.NewClient("23", "Secret", "42", omnia.WithMiddleware(correlation))
+type Middleware func(next RoundTrip) RoundTrip
+
+// Adds the given middlewares to the chain of the client. The first middleware is
+// the outermost one, thus it sees the request first and the response last.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *Client) {
+		chain := make([]Middleware, 0, len(c.middleware)+len(middleware))
+		chain = append(chain, c.middleware...)
+		chain = append(chain, middleware...)
+		c.middleware = chain
+	}
+}
+
+// Returns the round trip of the client with all middlewares applied.
+func (o Client) roundTrip() RoundTrip {
+	var rsl RoundTrip = o.send
+	for i := len(o.middleware) - 1; i >= 0; i-- {
+		rsl = o.middleware[i](rsl)
+	}
+	return rsl
+}
+
+// Returns a middleware logging each call with its status and duration on the debug
+// level. The query parameters and headers are not logged.
+func LoggingMiddleware(logger Logger) Middleware {
+	return func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, req *Request) (*RawResponse, error) {
+			start := time.Now()
+			rsp, err := next(ctx, req)
+			if !logger.Enabled(DebugLevel) {
+				return rsp, err
+			}
+			fields := Fields{
+				"method":      req.Method,
+				"endpoint":    req.Endpoint,
+				"class":       string(req.Class),
+				"stream_type": string(req.StreamType),
+				"operation":   req.Operation,
+				"duration":    time.Since(start).String(),
+			}
+			if rsp != nil {
+				fields["status"] = rsp.StatusCode
+			}
+			if err != nil {
+				fields["error"] = err.Error()
+			}
+			logger.Debug("omnia call", fields)
+			return rsp, err
+		}
+	}
+}
+
+// Returns a middleware measuring the duration of each call (including retries and
+// waiting for the rate limiter). The response is nil if the call failed.
+func TimingMiddleware(observe func(req *Request, rsp *RawResponse, duration time.Duration, err error)) Middleware {
+	return func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, req *Request) (*RawResponse, error) {
+			start := time.Now()
+			rsp, err := next(ctx, req)
+			observe(req, rsp, time.Since(start), err)
+			return rsp, err
+		}
+	}
+}
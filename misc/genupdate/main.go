@@ -0,0 +1,261 @@
+// Generates the typed parameters for the update operation (like params.UpdateVideo)
+// from a snapshot of the editable attributes of a streamtype. A snapshot is the
+// JSON encoded result of Client.EditableAttributes, either the result only or the
+// complete response.
+//
+// Usage:
+//
+//	go run ./misc/genupdate -in misc/editable_attributes/videos.json -type UpdateVideo -out params/update_video.go
+//
+// A fresh snapshot can be fetched from your domain before generating by passing the
+// path to a configuration file (see OmniaFromFile) and the streamtype:
+//
+//	go run ./misc/genupdate -fetch omnia.json -stream videos -in misc/editable_attributes/videos.json ...
+//
+// The structs in the params package are regenerated with go generate.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	omnia "github.com/alex-berlin-tv/gomnia"
+	"github.com/alex-berlin-tv/gomnia/enum"
+)
+
+// Go types of the attribute types reported by omnia. Unknown types are treated as
+// strings.
+var goTypes = map[string]string{
+	"string":    "string",
+	"text":      "string",
+	"html":      "string",
+	"url":       "string",
+	"int":       "int",
+	"integer":   "int",
+	"number":    "int",
+	"timestamp": "int",
+	"float":     "float64",
+	"decimal":   "float64",
+	"bool":      "enum.Bool",
+	"boolean":   "enum.Bool",
+}
+
+// Width of the generated comments.
+const commentWidth = 80
+
+type field struct {
+	Name    string
+	Type    string
+	Key     string
+	Comment []string
+}
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by genupdate from {{ .Source }}; DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+{{- if .UsesEnum }}
+	"github.com/alex-berlin-tv/gomnia/enum"
+{{- end }}
+	"github.com/pasztorpisti/qs"
+)
+
+// Editable attributes of {{ .Subject }} for the update operation of the Management
+// API. Only set attributes are sent. Use [Custom] (optionally together with [Merge])
+// to reset an attribute to an empty value or to set attributes unknown to this
+// struct.
+type {{ .Type }} struct {
+{{- range .Fields }}
+{{- range .Comment }}
+	// {{ . }}
+{{- end }}
+	{{ .Name }} {{ .Type }} ` + "`" + `qs:"{{ .Key }},omitempty"` + "`" + `
+{{- end }}
+}
+
+func (u {{ .Type }}) UrlEncode() (string, error) {
+	return qs.Marshal(&u)
+}
+`))
+
+func main() {
+	in := flag.String("in", "", "path to the snapshot of the editable attributes")
+	out := flag.String("out", "", "path of the generated file, stdout if empty")
+	typeName := flag.String("type", "", "name of the generated struct")
+	subject := flag.String("subject", "an item", "what the struct describes, used in its documentation")
+	pkg := flag.String("package", "params", "package of the generated file")
+	fetch := flag.String("fetch", "", "configuration file of a domain, fetch a fresh snapshot before generating")
+	stream := flag.String("stream", "", "streamtype of the snapshot to fetch")
+	flag.Parse()
+	if *in == "" || *typeName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *fetch != "" {
+		if err := fetchSnapshot(*fetch, enum.StreamType(*stream), *in); err != nil {
+			log.Fatalf("couldn't fetch snapshot, %s", err)
+		}
+	}
+	attributes, err := readSnapshot(*in)
+	if err != nil {
+		log.Fatalf("couldn't read snapshot %s, %s", *in, err)
+	}
+	src, err := generate(*pkg, *typeName, *subject, *in, attributes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// Saves the editable attributes of the streamtype to the given path.
+func fetchSnapshot(config string, streamType enum.StreamType, path string) error {
+	if streamType == "" {
+		return fmt.Errorf("no streamtype given")
+	}
+	client, err := omnia.OmniaFromFile(config)
+	if err != nil {
+		return err
+	}
+	rsp, err := client.EditableAttributes(streamType)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(rsp.Result, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func readSnapshot(path string) (omnia.EditableAttributesResponse, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rsp omnia.Response[omnia.EditableAttributesResponse]
+	if err := json.Unmarshal(data, &rsp); err == nil && rsp.Result != nil {
+		return rsp.Result, nil
+	}
+	var rsl omnia.EditableAttributesResponse
+	err = json.Unmarshal(data, &rsl)
+	return rsl, err
+}
+
+func generate(pkg, typeName, subject, source string, attributes omnia.EditableAttributesResponse) ([]byte, error) {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	usesEnum := false
+	fields := make([]field, len(keys))
+	for i, key := range keys {
+		property := attributes[key]
+		fields[i] = field{
+			Name:    goName(key),
+			Type:    goType(property.Type),
+			Key:     key,
+			Comment: wrap(comment(key, property), commentWidth-len("\t// ")),
+		}
+		if fields[i].Type == "enum.Bool" {
+			usesEnum = true
+		}
+	}
+	var buf bytes.Buffer
+	err := fileTemplate.Execute(&buf, map[string]interface{}{
+		"Source":   source,
+		"Package":  pkg,
+		"Type":     typeName,
+		"Subject":  subject,
+		"UsesEnum": usesEnum,
+		"Fields":   fields,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// Converts the name of an attribute to an exported Go name, e.g. isPicked to
+// IsPicked and age_restriction to AgeRestriction.
+func goName(key string) string {
+	var rsl strings.Builder
+	upper := true
+	for _, r := range key {
+		if r == '_' || r == '-' || r == ' ' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		rsl.WriteRune(r)
+	}
+	return rsl.String()
+}
+
+func goType(apiType string) string {
+	if rsl, ok := goTypes[strings.ToLower(apiType)]; ok {
+		return rsl
+	}
+	return "string"
+}
+
+func comment(key string, property omnia.EditableAttributesProperties) string {
+	var parts []string
+	if property.Hint != "" {
+		parts = append(parts, strings.TrimSuffix(property.Hint, ".")+".")
+	} else {
+		parts = append(parts, fmt.Sprintf("The %s attribute.", key))
+	}
+	if strings.ToLower(property.Type) == "timestamp" {
+		parts = append(parts, "As UNIX timestamp.")
+	}
+	if goType(property.Type) == "string" && property.MaxLength > 0 {
+		parts = append(parts, fmt.Sprintf("Max. %d characters.", property.MaxLength))
+	}
+	if property.Format != "" {
+		parts = append(parts, fmt.Sprintf("Format: %s.", property.Format))
+	}
+	if property.AllowedInUgc == 0 {
+		parts = append(parts, "Not editable for UGC items.")
+	}
+	return strings.Join(parts, " ")
+}
+
+// Wraps the text into lines of at most the given width.
+func wrap(text string, width int) []string {
+	var rsl []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > width {
+			rsl = append(rsl, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		rsl = append(rsl, line)
+	}
+	return rsl
+}
@@ -5,7 +5,6 @@ import (
 
 	"github.com/alex-berlin-tv/gomnia/enum"
 	"github.com/alex-berlin-tv/gomnia/types"
-	log "github.com/sirupsen/logrus"
 )
 
 // Metadata part of an API response.
@@ -35,7 +34,7 @@ type ResponseMetadata struct {
 	FromCache *int `json:"fromcache,omitempty"`
 }
 
-func (m ResponseMetadata) toMap() map[string]interface{} {
+func (m ResponseMetadata) toMap() (Fields, error) {
 	return structToMap(m)
 }
 
@@ -49,7 +48,7 @@ type ResponsePaging struct {
 	ResultCount int `json:"resultcount"`
 }
 
-func (p ResponsePaging) toMap() map[string]interface{} {
+func (p ResponsePaging) toMap() (Fields, error) {
 	return structToMap(p)
 }
 
@@ -77,6 +76,14 @@ type MediaResultItem struct {
 	ConnectedMedia MediaResultConnectedMedia `json:"connectedmedia"`
 }
 
+// ContainerResultItem is a media item of a container streamtype (like a show or a
+// playlist) together with the media items it contains. Use the
+// »childMediaDetails« parameter to control the details of the child media.
+type ContainerResultItem struct {
+	MediaResultItem
+	ChildMedia MediaResult `json:"childmedia"`
+}
+
 // MediaResultGeneral provides general information about a media item, including
 // its ID, title, and more.
 //
@@ -113,6 +120,9 @@ type MediaResultGeneral struct {
 	Category                 string             `json:"category"`
 	Description              string             `json:"description"`
 	ReferenceNumber          string             `json:"refnr"`
+	// Relevance of the item for the query. Only available for results of
+	// [Client.ByQuery] in the fulltext query mode.
+	QueryScore *float64 `json:"queryScore,omitempty"`
 }
 
 // MediaResultImageData contains image-related data for a media item, including
@@ -141,6 +151,40 @@ type MediaResultConnectedMedia struct {
 	Shows []MediaResultGeneral `json:"shows"`
 }
 
+// ManagementResult identifies the item changed by a Management API call (like
+// [Client.Unpublish]).
+type ManagementResult struct {
+	Id   int    `json:"ID"`
+	Gid  int    `json:"GID"`
+	Hash string `json:"hash"`
+}
+
+// ProcessingStatus reports the processing of the new source file of an item after
+// [Client.ReplaceFile] or [Client.ReplaceFileUpload].
+type ProcessingStatus struct {
+	ManagementResult
+	State enum.ProcessingState `json:"state"`
+	// Whether the covers are generated anew from the new file.
+	RegenerateCovers enum.Bool `json:"regenerateCovers"`
+	// Whether the waveform (see [MediaResultImageData.Waveform]) is generated
+	// anew from the new file.
+	RegenerateWaveform enum.Bool `json:"regenerateWaveform"`
+	// MD5 checksum (hex encoded) of the file as received by omnia. Only set for
+	// uploaded files.
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// CoverResult describes a cover of an item after it was set or changed (see
+// [Client.SetCoverFromURL]).
+type CoverResult struct {
+	ManagementResult
+	Variant enum.CoverType `json:"variant"`
+	// URL of the cover as delivered by omnia.
+	URL         string `json:"url"`
+	Description string `json:"description"`
+	Language    string `json:"language"`
+}
+
 // EditableAttributesResponse is a map that associates attribute names with their
 // editable properties.
 type EditableAttributesResponse map[string]EditableAttributesProperties
@@ -158,12 +202,14 @@ type EditableAttributesProperties struct {
 // The available YouTube categories. An id mapped to the name of the category.
 type YouTubeCategories map[int]string
 
-func structToMap(data interface{}) map[string]interface{} {
-	var rsl map[string]interface{}
+func structToMap(data interface{}) (Fields, error) {
+	var rsl Fields
 	tmp, err := json.Marshal(data)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+	if err := json.Unmarshal(tmp, &rsl); err != nil {
+		return nil, err
 	}
-	json.Unmarshal(tmp, &rsl)
-	return rsl
+	return rsl, nil
 }
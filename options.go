@@ -0,0 +1,89 @@
+package gomnia
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// The base URL of the nexxOMNIA API used if no other URL is set using
+// [WithBaseURL].
+const DefaultBaseURL = "https://api.nexx.cloud/v3.1"
+
+// Time a single API request is allowed to take if neither a custom timeout
+// nor a custom HTTP client is set.
+const DefaultTimeout = 10 * time.Second
+
+// The HTTP client used if no custom client is set with [WithHTTPClient]. It's
+// shared between all [Client] instances so the connection pool is reused.
+var defaultHttpClient = &http.Client{}
+
+// Alters the configuration of a [Client]. Pass options to [NewClient].
+type ClientOption func(*Client)
+
+// Use the given HTTP client for all requests. This allows the use of custom
+// transports (proxies, connection pools, test doubles). If no timeout is set
+// using [WithTimeout] the timeout of the given client is used.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// Send all requests to the given base URL instead of [DefaultBaseURL]. The URL
+// has to contain the API version, e.g. `https://api.nexx.cloud/v3.1`. Useful to
+// target a staging gateway or a local stand-in.
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseUrl = strings.TrimSuffix(url, "/")
+	}
+}
+
+// Sets the maximum duration of a single API request (including reading the
+// response body). A value of zero or less disables the timeout. Requests of
+// [Client.AllPaged] are subject to this timeout one by one.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		if timeout <= 0 {
+			timeout = -1
+		}
+		c.timeout = timeout
+	}
+}
+
+// Sets the User-Agent header for all requests.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// Returns the base URL requests are sent to.
+func (o Client) apiBaseUrl() string {
+	if o.baseUrl == "" {
+		return DefaultBaseURL
+	}
+	return o.baseUrl
+}
+
+// Returns the HTTP client requests are sent with.
+func (o Client) client() *http.Client {
+	if o.httpClient == nil {
+		return defaultHttpClient
+	}
+	return o.httpClient
+}
+
+// Returns the timeout for a single request. Zero states that no timeout should
+// be applied by the library itself.
+func (o Client) requestTimeout() time.Duration {
+	switch {
+	case o.timeout < 0:
+		return 0
+	case o.timeout > 0:
+		return o.timeout
+	case o.httpClient != nil:
+		return 0
+	}
+	return DefaultTimeout
+}
@@ -0,0 +1,333 @@
+package gomnia
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/alex-berlin-tv/gomnia/params"
+)
+
+// Same as [Client.AllPaged] but fetches the pages concurrently using the given
+// number of workers. The first page is requested on its own in order to learn the
+// total number of items, all remaining pages are then fetched in parallel. The items
+// of the result keep the order of the API. If any page fails, all pending requests
+// are canceled and a [*PagingError] is returned.
+func (o Client) AllPagedConcurrent(streamType enum.StreamType, parameters params.QueryParameters, workers int) (*Response[MediaResult], error) {
+	return o.AllPagedConcurrentCtx(context.Background(), streamType, parameters, workers)
+}
+
+// Same as [Client.AllPagedConcurrent] but all page requests are bound to the given
+// context.
+func (o Client) AllPagedConcurrentCtx(ctx context.Context, streamType enum.StreamType, parameters params.QueryParameters, workers int) (*Response[MediaResult], error) {
+	if workers < 1 {
+		workers = 1
+	}
+	return o.allPaged(ctx, streamType, parameters, workers)
+}
+
+// Start over if the total number of items changes while paging through a listing.
+// The paging is restarted at most the given number of times, after that the result
+// is returned together with a [*ResultCountChangedError].
+func WithPagingRestarts(restarts int) ClientOption {
+	return func(c *Client) {
+		c.pagingRestarts = restarts
+	}
+}
+
+// Fetches all pages of the all operation. If workers is zero, the pages are
+// fetched one after another, otherwise concurrently using the given number of
+// workers.
+func (o Client) allPaged(ctx context.Context, streamType enum.StreamType, parameters params.QueryParameters, workers int) (rsl *Response[MediaResult], err error) {
+	ctx, span := o.tracing().Start(ctx, "gomnia.AllPaged",
+		Attribute{Key: "omnia.domain_id", Value: o.DomainId},
+		Attribute{Key: "omnia.stream_type", Value: string(streamType)},
+		Attribute{Key: "omnia.paging.workers", Value: workers},
+	)
+	defer func() {
+		if rsl != nil {
+			span.SetAttributes(Attribute{Key: "omnia.paging.items", Value: len(rsl.Result)})
+		}
+		endSpan(span, err)
+	}()
+	for restart := 0; ; restart++ {
+		if workers == 0 {
+			rsl, err = o.allPagedSequential(ctx, streamType, parameters)
+		} else {
+			rsl, err = o.allPagedConcurrent(ctx, streamType, parameters, workers)
+		}
+		var changed *ResultCountChangedError
+		if errors.As(err, &changed) && restart < o.pagingRestarts {
+			o.log().Debug("restart paging", Fields{"reason": changed.Error()})
+			continue
+		}
+		return rsl, err
+	}
+}
+
+func (o Client) allPagedSequential(ctx context.Context, streamType enum.StreamType, parameters params.QueryParameters) (*Response[MediaResult], error) {
+	pgr := newPager()
+	var rqs *Response[MediaResult]
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		rsp, err := o.fetchPage(ctx, streamType, AllOperation, nil, parameters, pgr.next)
+		if err != nil {
+			return nil, err
+		}
+		items := pgr.add(rsp)
+		if rqs == nil {
+			rqs = rsp
+			rqs.Result = items
+		} else {
+			rqs.Result = append(rqs.Result, items...)
+		}
+		if pgr.done(rsp) {
+			break
+		}
+	}
+	return rqs, pgr.err()
+}
+
+func (o Client) allPagedConcurrent(ctx context.Context, streamType enum.StreamType, parameters params.QueryParameters, workers int) (*Response[MediaResult], error) {
+	pgr := newPager()
+	rqs, err := o.fetchPage(ctx, streamType, AllOperation, nil, parameters, 0)
+	if err != nil {
+		return nil, err
+	}
+	rqs.Result = pgr.add(rqs)
+	if pgr.done(rqs) {
+		return rqs, pgr.err()
+	}
+	var offsets []int
+	for start := pgr.next; start < pgr.resultCount; start += pgr.limit {
+		offsets = append(offsets, start)
+	}
+	pages, err := o.fetchPages(ctx, streamType, parameters, offsets, workers)
+	if err != nil {
+		return nil, err
+	}
+	for _, page := range pages {
+		rqs.Result = append(rqs.Result, pgr.add(page)...)
+	}
+	return rqs, pgr.err()
+}
+
+// Fetches the pages at the given offsets of the all operation concurrently. The
+// returned pages are in the same order as the offsets.
+func (o Client) fetchPages(ctx context.Context, streamType enum.StreamType, parameters params.QueryParameters, offsets []int, workers int) ([]*Response[MediaResult], error) {
+	if workers < 1 {
+		workers = 1
+	}
+	pages := make([]*Response[MediaResult], len(offsets))
+	errs := make([]error, len(offsets))
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				rsp, err := o.fetchPage(workerCtx, streamType, AllOperation, nil, parameters, offsets[i])
+				if err != nil {
+					errs[i] = err
+					cancel()
+					continue
+				}
+				pages[i] = rsp
+			}
+		}()
+	}
+feed:
+	for i := range offsets {
+		select {
+		case jobs <- i:
+		case <-workerCtx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	pagingErr := &PagingError{}
+	for i, err := range errs {
+		// Pages aborted due to the failure of another page are not reported.
+		if err == nil || errors.Is(err, context.Canceled) {
+			continue
+		}
+		pagingErr.Pages = append(pagingErr.Pages, PageError{Start: offsets[i], Err: err})
+	}
+	if len(pagingErr.Pages) > 0 {
+		return nil, pagingErr
+	}
+	return pages, nil
+}
+
+// The failure of a single page.
+type PageError struct {
+	// Offset of the failed page.
+	Start int
+	// The error of the page request.
+	Err error
+}
+
+// Returned if one or more pages failed to load while fetching them concurrently.
+type PagingError struct {
+	// All failed pages ordered by their offset.
+	Pages []PageError
+}
+
+func (e *PagingError) Error() string {
+	parts := make([]string, len(e.Pages))
+	for i, page := range e.Pages {
+		parts[i] = fmt.Sprintf("page at %d: %s", page.Start, page.Err)
+	}
+	return fmt.Sprintf("failed to fetch %d page(s), %s", len(e.Pages), strings.Join(parts, "; "))
+}
+
+// Returns the errors of all failed pages.
+func (e *PagingError) Unwrap() []error {
+	rsl := make([]error, len(e.Pages))
+	for i, page := range e.Pages {
+		rsl[i] = page.Err
+	}
+	return rsl
+}
+
+// Reports whether the error of any failed page matches the target. Needed as
+// errors.Is only follows Unwrap() []error as of Go 1.20.
+func (e *PagingError) Is(target error) bool {
+	for _, page := range e.Pages {
+		if errors.Is(page.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Finds the first error of the failed pages matching the target. Needed as
+// errors.As only follows Unwrap() []error as of Go 1.20.
+func (e *PagingError) As(target any) bool {
+	for _, page := range e.Pages {
+		if errors.As(page.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fetches a single page of a listing operation within its own span.
+func (o Client) fetchPage(ctx context.Context, streamType enum.StreamType, operation ListOperation, args []string, parameters params.QueryParameters, start int) (*Response[MediaResult], error) {
+	ctx, span := o.tracing().Start(ctx, "gomnia.page", Attribute{Key: "omnia.paging.start", Value: start})
+	// The offset of a page can be 1 if the limit is 1, CallCtx would treat it as
+	// the first page.
+	rsp, err := universalCall(ctx, o, "get", streamType, mediaApiType{}, string(operation), args, "", parameters, start, Response[MediaResult]{})
+	if rsp != nil && rsp.Paging != nil {
+		span.SetAttributes(Attribute{Key: "omnia.paging.result_count", Value: rsp.Paging.ResultCount})
+	}
+	endSpan(span, err)
+	return rsp, err
+}
+
+// Keeps track of the progress while paging through a listing. The offset of the
+// next page is computed from the paging information of the last response, items
+// already seen on a previous page are dropped and changes of the total number of
+// items are recorded.
+type pager struct {
+	next        int
+	limit       int
+	resultCount int
+	seen        map[int]struct{}
+	changed     *ResultCountChangedError
+}
+
+func newPager() *pager {
+	return &pager{
+		limit:       pageSize,
+		resultCount: -1,
+		seen:        map[int]struct{}{},
+	}
+}
+
+// Processes a fetched page and returns the items which weren't seen before.
+func (p *pager) add(rsp *Response[MediaResult]) MediaResult {
+	if rsp.Paging != nil {
+		if p.resultCount < 0 {
+			p.resultCount = rsp.Paging.ResultCount
+		} else if rsp.Paging.ResultCount != p.resultCount && p.changed == nil {
+			p.changed = &ResultCountChangedError{
+				Initial: p.resultCount,
+				Current: rsp.Paging.ResultCount,
+				Start:   rsp.Paging.Start,
+			}
+		}
+		if rsp.Paging.Limit > 0 {
+			p.limit = rsp.Paging.Limit
+		}
+		p.next = rsp.Paging.Start + p.limit
+	} else {
+		p.next += len(rsp.Result)
+	}
+	rsl := make(MediaResult, 0, len(rsp.Result))
+	for _, item := range rsp.Result {
+		id := item.General.Id
+		if id != 0 {
+			if _, ok := p.seen[id]; ok {
+				continue
+			}
+			p.seen[id] = struct{}{}
+		}
+		rsl = append(rsl, item)
+	}
+	return rsl
+}
+
+// Whether the given response was the last page of the listing.
+func (p *pager) done(rsp *Response[MediaResult]) bool {
+	if rsp.Paging == nil || len(rsp.Result) == 0 {
+		return true
+	}
+	return p.next >= rsp.Paging.ResultCount
+}
+
+// Returns an error if the total number of items changed while paging.
+func (p *pager) err() error {
+	if p.changed == nil {
+		return nil
+	}
+	return p.changed
+}
+
+// The total number of items changed while paging through a listing. Can be used
+// with [errors.Is], see [ResultCountChangedError] for details.
+var ErrResultCountChanged = errors.New("result count changed while paging")
+
+// Returned if the total number of items of a listing changed while paging through
+// it. Items might be missing from the result as they moved to an already fetched
+// page.
+type ResultCountChangedError struct {
+	// Total number of items reported by the first page.
+	Initial int
+	// Total number of items reported by the page where the change was noticed.
+	Current int
+	// Offset of the page where the change was noticed.
+	Start int
+}
+
+func (e *ResultCountChangedError) Error() string {
+	return fmt.Sprintf("%s, from %d to %d items (noticed at %d)", ErrResultCountChanged, e.Initial, e.Current, e.Start)
+}
+
+func (e *ResultCountChangedError) Is(target error) bool {
+	return target == ErrResultCountChanged
+}
@@ -0,0 +1,259 @@
+package gomnia_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	omnia "github.com/alex-berlin-tv/gomnia"
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/alex-berlin-tv/gomnia/params"
+)
+
+// Limit enforced by the listing server regardless of the requested limit.
+const listingLimit = 2
+
+// Serves the all operation with pages of at most listingLimit items. The page
+// function returns the IDs at the given offset and the total number of items, pass
+// counts the requests of the first page so far. A negative count fails the request
+// with a 404.
+type listing struct {
+	mutex  sync.Mutex
+	starts []int
+	passes int
+	page   func(pass, start int) (ids []int, count int)
+}
+
+func (l *listing) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+	l.mutex.Lock()
+	l.starts = append(l.starts, start)
+	if start == 0 {
+		l.passes++
+	}
+	ids, count := l.page(l.passes, start)
+	l.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if count < 0 {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"metadata":{"status":404,"errorhint":"not found"}}`))
+		return
+	}
+	result := make([]map[string]interface{}, len(ids))
+	for i, id := range ids {
+		result[i] = map[string]interface{}{"general": map[string]interface{}{"ID": id}}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"metadata": map[string]interface{}{"status": 200},
+		"result":   result,
+		"paging":   map[string]interface{}{"start": start, "limit": listingLimit, "resultcount": count},
+	})
+}
+
+func (l *listing) requestedStarts() []int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return append([]int(nil), l.starts...)
+}
+
+func newListing(t *testing.T, page func(pass, start int) ([]int, int), opts ...omnia.ClientOption) (*listing, omnia.Client) {
+	t.Helper()
+	l := &listing{page: page}
+	srv := httptest.NewServer(l)
+	t.Cleanup(srv.Close)
+	opts = append(opts, omnia.WithBaseURL(srv.URL))
+	return l, omnia.NewClient("1", "secret", "session", opts...)
+}
+
+// Returns the IDs at the offset of a listing with the given number of items.
+func window(start, count int) []int {
+	var rsl []int
+	for id := start + 1; id <= start+listingLimit && id <= count; id++ {
+		rsl = append(rsl, id)
+	}
+	return rsl
+}
+
+func ids(result omnia.MediaResult) []int {
+	rsl := make([]int, len(result))
+	for i, item := range result {
+		rsl[i] = item.General.Id
+	}
+	return rsl
+}
+
+func TestAllPagedOffsets(t *testing.T) {
+	fetchers := map[string]func(omnia.Client) (*omnia.Response[omnia.MediaResult], error){
+		"sequential": func(c omnia.Client) (*omnia.Response[omnia.MediaResult], error) {
+			return c.AllPaged(enum.AudioStreamType, nil)
+		},
+		"concurrent": func(c omnia.Client) (*omnia.Response[omnia.MediaResult], error) {
+			return c.AllPagedConcurrent(enum.AudioStreamType, nil, 3)
+		},
+	}
+	for name, fetch := range fetchers {
+		t.Run(name, func(t *testing.T) {
+			l, client := newListing(t, func(pass, start int) ([]int, int) {
+				return window(start, 5), 5
+			})
+			rsl, err := fetch(client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := ids(rsl.Result), []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(got, want) {
+				t.Fatalf("got items %v, want %v", got, want)
+			}
+			starts := l.requestedStarts()
+			if len(starts) != 3 || starts[0] != 0 {
+				t.Fatalf("requested offsets %v, want 0, 2 and 4", starts)
+			}
+			seen := map[int]bool{}
+			for _, start := range starts {
+				seen[start] = true
+			}
+			if !seen[2] || !seen[4] {
+				t.Fatalf("requested offsets %v, want 0, 2 and 4", starts)
+			}
+		})
+	}
+}
+
+func TestAllPagedDeduplicates(t *testing.T) {
+	// Every page after the first one starts with the last item of the previous page.
+	_, client := newListing(t, func(pass, start int) ([]int, int) {
+		if start == 0 {
+			return window(0, 5), 5
+		}
+		return window(start-1, 5), 5
+	})
+	rsl, err := client.AllPaged(enum.AudioStreamType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(rsl.Result), []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got items %v, want %v", got, want)
+	}
+}
+
+func TestAllPagedResultCountChanged(t *testing.T) {
+	// An item is added after the first page was fetched.
+	_, client := newListing(t, func(pass, start int) ([]int, int) {
+		if start == 0 {
+			return window(0, 5), 5
+		}
+		return window(start, 6), 6
+	})
+	rsl, err := client.AllPaged(enum.AudioStreamType, nil)
+	if !errors.Is(err, omnia.ErrResultCountChanged) {
+		t.Fatalf("got error %v, want ErrResultCountChanged", err)
+	}
+	var changed *omnia.ResultCountChangedError
+	if !errors.As(err, &changed) {
+		t.Fatalf("got error %T, want *ResultCountChangedError", err)
+	}
+	if changed.Initial != 5 || changed.Current != 6 || changed.Start != 2 {
+		t.Fatalf("unexpected error %+v", changed)
+	}
+	if rsl == nil || len(rsl.Result) != 6 {
+		t.Fatalf("the collected items weren't returned with the error: %+v", rsl)
+	}
+}
+
+func TestWithPagingRestarts(t *testing.T) {
+	// The item count only changes during the first pass.
+	page := func(pass, start int) ([]int, int) {
+		if pass == 1 && start == 0 {
+			return window(0, 5), 5
+		}
+		return window(start, 6), 6
+	}
+
+	l, client := newListing(t, page, omnia.WithPagingRestarts(1))
+	rsl, err := client.AllPaged(enum.AudioStreamType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := ids(rsl.Result), []int{1, 2, 3, 4, 5, 6}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got items %v, want %v", got, want)
+	}
+	if l.passes != 2 {
+		t.Fatalf("paging ran %d times, want 2", l.passes)
+	}
+
+	l, client = newListing(t, page)
+	if _, err := client.AllPaged(enum.AudioStreamType, nil); !errors.Is(err, omnia.ErrResultCountChanged) {
+		t.Fatalf("got error %v without restarts, want ErrResultCountChanged", err)
+	}
+	if l.passes != 1 {
+		t.Fatalf("paging ran %d times without restarts, want 1", l.passes)
+	}
+}
+
+func TestCallPagingStart(t *testing.T) {
+	l, client := newListing(t, func(pass, start int) ([]int, int) {
+		return window(start, 5), 5
+	})
+	_, err := omnia.Call(client, "get", enum.AudioStreamType, "all", nil, nil, -1, omnia.Response[omnia.MediaResult]{})
+	if !errors.Is(err, omnia.ErrValidation) {
+		t.Errorf("pagingStart -1 returned %v, want ErrValidation", err)
+	}
+	if starts := l.requestedStarts(); len(starts) != 0 {
+		t.Fatalf("invalid calls were sent with offsets %v", starts)
+	}
+
+	tests := []struct {
+		pagingStart int
+		parameters  params.QueryParameters
+		want        []int
+	}{
+		{0, nil, []int{1, 2}},
+		// The deprecated value for the first page.
+		{1, nil, []int{1, 2}},
+		{1, params.Basic{Start: 1}, []int{2, 3}},
+		{0, params.Basic{Start: 1}, []int{2, 3}},
+		{2, params.Basic{Start: 1}, []int{3, 4}},
+	}
+	for _, tt := range tests {
+		rsl, err := omnia.Call(client, "get", enum.AudioStreamType, "all", nil, tt.parameters, tt.pagingStart, omnia.Response[omnia.MediaResult]{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(rsl.Result); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("pagingStart %d with %+v returned %v, want %v", tt.pagingStart, tt.parameters, got, tt.want)
+		}
+	}
+}
+
+func TestAllPagedConcurrentPageError(t *testing.T) {
+	_, client := newListing(t, func(pass, start int) ([]int, int) {
+		if start == 2 {
+			return nil, -1
+		}
+		return window(start, 5), 5
+	})
+
+	_, err := client.AllPagedConcurrent(enum.AudioStreamType, nil, 3)
+	var pagingErr *omnia.PagingError
+	if !errors.As(err, &pagingErr) {
+		t.Fatalf("got error %T, want *PagingError", err)
+	}
+	if len(pagingErr.Pages) == 0 || pagingErr.Pages[0].Start != 2 {
+		t.Fatalf("unexpected failed pages %+v", pagingErr.Pages)
+	}
+	if !errors.Is(err, omnia.ErrNotFound) {
+		t.Fatalf("got error %v, want ErrNotFound", err)
+	}
+	var apiErr *omnia.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode() != http.StatusNotFound {
+		t.Fatalf("got error %v, want the APIError of the failed page", err)
+	}
+	if errors.Is(err, omnia.ErrUnauthorized) {
+		t.Fatal("the error matches ErrUnauthorized")
+	}
+}
@@ -0,0 +1,17 @@
+package params
+
+import (
+	"github.com/pasztorpisti/qs"
+)
+
+// Parameters for the archive ManagementAPI call. The documentation can be found [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#archive
+type Archive struct {
+	// A free text as reason.
+	Reason string `qs:"reason,omitempty"`
+}
+
+func (a Archive) UrlEncode() (string, error) {
+	return qs.Marshal(&a)
+}
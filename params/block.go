@@ -0,0 +1,17 @@
+package params
+
+import (
+	"github.com/pasztorpisti/qs"
+)
+
+// Parameters for the block ManagementAPI call. The documentation can be found [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#block
+type Block struct {
+	// A free text as reason.
+	Reason string `qs:"reason,omitempty"`
+}
+
+func (b Block) UrlEncode() (string, error) {
+	return qs.Marshal(&b)
+}
@@ -1,20 +1,25 @@
 package params
 
 import (
+	"strings"
+
 	"github.com/alex-berlin-tv/gomnia/enum"
 	"github.com/pasztorpisti/qs"
 )
 
-// Parameters for the byquery MediaAPI call. The documentation is available [here].
+// Parameters for the byquery MediaAPI call. The [Basic] and [General] parameters
+// can be combined with the query specific ones. The documentation is available
+// [here].
 //
 // [here]: https://api.docs.nexx.cloud/media-api/endpoints/media-endpoint#byquery
 type ByQuery struct {
 	Basic
+	General
 	// Defines the Way, the Query is executed. Fore more results, "classicwithor"
 	// is optimal. For a Lucene Search with Relevance, use "fulltext".
 	QueryMode enum.QueryMode `qs:"queryMode,omitempty"`
-	// A comma separated List of Attributes, to search within. If omitted, the
-	// Search will use all available Text Attributes.
+	// A List of Attributes, to search within. If omitted, the Search will use
+	// all available Text Attributes. Sent as comma separated list.
 	QueryFields []string `qs:"queryFields,omitempty"`
 	// Skip Results with a Query Score lower than the given Value. Only useful
 	// for query-mode "fulltext".
@@ -23,12 +28,24 @@ type ByQuery struct {
 	// Substring Matches shall be returned, set this Parameter to 1. Only useful,
 	// if query-mode is not "fulltext".
 	IncludeSubstringMatches bool `qs:"includeSubstringMatches,omitempty"`
-	// By default, the Query will only return Results on full Words. If also
-	// Substring Matches shall be returned, set this Parameter to 1. Only useful,
-	// if query-mode is not "fulltext".
+	// By default, each Query is added to the Search Statistics of the Domain.
+	// Set this to skip the Reporting, e.g. for automated Queries.
 	SkipReporting bool `qs:"skipReporting,omitempty"`
 }
 
 func (b ByQuery) UrlEncode() (string, error) {
-	return qs.Marshal(&b)
+	values, err := qs.MarshalValues(&b)
+	if err != nil {
+		return "", err
+	}
+	if fields, ok := values["queryFields"]; ok {
+		values.Set("queryFields", strings.Join(fields, ","))
+	}
+	// omnia expects 0 and 1 for boolean values.
+	for _, key := range []string{"includeSubstringMatches", "skipReporting"} {
+		if values.Get(key) == "true" {
+			values.Set(key, string(enum.YesBool))
+		}
+	}
+	return values.Encode(), nil
 }
@@ -0,0 +1,92 @@
+package params
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/pasztorpisti/qs"
+)
+
+// Two-letter language codes according to ISO 639-1.
+var languagePattern = regexp.MustCompile(`^[a-z]{2}$`)
+
+// Parameters for setting a cover from a remote image with the addcover ManagementAPI
+// call. The documentation can be found [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#covers
+type CoverFromURL struct {
+	// The URL of the image. Has to be publicly reachable via HTTP(S).
+	URL string `qs:"url"`
+	// Description (alt text) of the cover.
+	Description string `qs:"description,omitempty"`
+	// Language of the cover as ISO 639-1 code.
+	Language string `qs:"language,omitempty"`
+}
+
+func (c CoverFromURL) UrlEncode() (string, error) {
+	return qs.Marshal(&c)
+}
+
+// Checks if the instance is valid for the API. Returns an error with an
+// explanation.
+func (c CoverFromURL) Validate() error {
+	if err := validateURL(c.URL); err != nil {
+		return err
+	}
+	return validateLanguage(c.Language)
+}
+
+// Parameters for uploading a local image as cover with the uploadcover ManagementAPI
+// call. The documentation can be found [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#covers
+type CoverUpload struct {
+	// The filename of the image including its extension.
+	Filename string `qs:"filename"`
+	// Description (alt text) of the cover.
+	Description string `qs:"description,omitempty"`
+	// Language of the cover as ISO 639-1 code.
+	Language string `qs:"language,omitempty"`
+}
+
+func (c CoverUpload) UrlEncode() (string, error) {
+	return qs.Marshal(&c)
+}
+
+// Checks if the instance is valid for the API. Returns an error with an
+// explanation.
+func (c CoverUpload) Validate() error {
+	if c.Filename == "" {
+		return fmt.Errorf("filename has to be set")
+	}
+	return validateLanguage(c.Language)
+}
+
+// Parameters for the updatecover ManagementAPI call which changes the description
+// and language of an existing cover. The documentation can be found [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#covers
+type CoverDescription struct {
+	// Description (alt text) of the cover. An empty description removes the
+	// existing one.
+	Description string `qs:"description"`
+	// Language of the cover as ISO 639-1 code. The language is kept if not set.
+	Language string `qs:"language,omitempty"`
+}
+
+func (c CoverDescription) UrlEncode() (string, error) {
+	return qs.Marshal(&c)
+}
+
+// Checks if the instance is valid for the API. Returns an error with an
+// explanation.
+func (c CoverDescription) Validate() error {
+	return validateLanguage(c.Language)
+}
+
+func validateLanguage(language string) error {
+	if language != "" && !languagePattern.MatchString(language) {
+		return fmt.Errorf("language has to be a ISO 639-1 code, %s given", language)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package params
+
+import (
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/pasztorpisti/qs"
+)
+
+// Parameters for the remove ManagementAPI call. The documentation can be found [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#remove
+type Delete struct {
+	// Whether the item is moved to the trash (and can be restored) or deleted
+	// finally. Defaults to a soft delete.
+	Mode enum.DeleteMode `qs:"mode,omitempty"`
+}
+
+func (d Delete) UrlEncode() (string, error) {
+	return qs.Marshal(&d)
+}
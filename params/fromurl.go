@@ -0,0 +1,76 @@
+package params
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/pasztorpisti/qs"
+)
+
+// Parameters for the fromurl ManagementAPI call which creates a new item from a
+// remote file. The documentation can be found [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#fromurl
+type FromURL struct {
+	// The URL of the source file. Has to be publicly reachable via HTTP(S).
+	URL string `qs:"url"`
+	// Optional filename of the source file, if it can't be derived from the URL.
+	Filename string `qs:"filename,omitempty"`
+	// The title of the new item.
+	Title string `qs:"title,omitempty"`
+	// The subtitle of the new item.
+	Subtitle string `qs:"subtitle,omitempty"`
+	// The description of the new item.
+	Description string `qs:"description,omitempty"`
+	// The reference number of the new item.
+	RefNr string `qs:"refnr,omitempty"`
+	// The ID of the channel the new item is assigned to.
+	Channel int `qs:"channel,omitempty"`
+	// If set to 1, the item is published as soon as the processing is finished.
+	AutoPublish enum.Bool `qs:"autoPublish,omitempty"`
+	// Internal notes for the new item.
+	Notes string `qs:"notes,omitempty"`
+	// If set to 1, the source file is fetched and processed asynchronously in the
+	// queue of the domain. The call returns as soon as the item is created.
+	UseQueue enum.Bool `qs:"useQueue,omitempty"`
+	// Start of the processing as UNIX timestamp if UseQueue is set. Processing
+	// starts immediately if not given.
+	QueueStart int `qs:"queueStart,omitempty"`
+}
+
+func (f FromURL) UrlEncode() (string, error) {
+	return qs.Marshal(&f)
+}
+
+// Checks if the instance is valid for the API. Returns an error with an
+// explanation.
+func (f FromURL) Validate() error {
+	return validateSource(f.URL, f.UseQueue, f.QueueStart)
+}
+
+// Checks the URL of a remote source file and the queue options.
+func validateSource(source string, useQueue enum.Bool, queueStart int) error {
+	if err := validateURL(source); err != nil {
+		return err
+	}
+	if queueStart != 0 && useQueue != enum.YesBool {
+		return fmt.Errorf("queueStart can only be used together with useQueue")
+	}
+	return nil
+}
+
+// Checks that the URL of a remote file is an absolute HTTP(S) URL.
+func validateURL(source string) error {
+	if source == "" {
+		return fmt.Errorf("url has to be set")
+	}
+	parsed, err := url.Parse(source)
+	if err != nil {
+		return fmt.Errorf("url is invalid, %s", err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("url has to be an absolute http(s) URL, %s given", source)
+	}
+	return nil
+}
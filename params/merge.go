@@ -0,0 +1,134 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Parameters given as URL values. Returned by [Merge] and can be passed to all API
+// calls.
+type Values url.Values
+
+func (v Values) UrlEncode() (string, error) {
+	return url.Values(v).Encode(), nil
+}
+
+// Precedence of a set of parameters when merged with [Merge].
+type precedence int
+
+const (
+	defaultPrecedence precedence = iota
+	regularPrecedence
+	overridePrecedence
+)
+
+// Parameters with a precedence other than the regular one.
+type prioritized struct {
+	parameters QueryParameters
+	precedence precedence
+}
+
+func (p prioritized) UrlEncode() (string, error) {
+	if p.parameters == nil {
+		return "", nil
+	}
+	return p.parameters.UrlEncode()
+}
+
+// Marks the parameters as defaults for [Merge]. Their values are only used for keys
+// which aren't set by any other parameters.
+func Default(parameters QueryParameters) QueryParameters {
+	return prioritized{parameters: parameters, precedence: defaultPrecedence}
+}
+
+// Marks the parameters as overrides for [Merge]. Their values replace the values of
+// all other parameters.
+func Override(parameters QueryParameters) QueryParameters {
+	return prioritized{parameters: parameters, precedence: overridePrecedence}
+}
+
+// Two parameters of the same precedence set a key to different values. Can be used
+// with [errors.Is], see [ConflictError] for details.
+var ErrConflict = errors.New("conflicting parameters")
+
+// Returned by [Merge] if two parameters of the same precedence set a key to
+// different values.
+type ConflictError struct {
+	// The conflicting key.
+	Key string
+	// Value set by the first parameters.
+	Value []string
+	// Value set by the later parameters.
+	Other []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s, %s set to %q and %q", ErrConflict, e.Key, strings.Join(e.Value, ","), strings.Join(e.Other, ","))
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// Combines multiple parameters into a single set. This allows to pass, for
+// example, [Basic] and [General] parameters to one call:
+//
+//	merged, err := params.Merge(params.Basic{Limit: 10}, params.General{Channel: 5})
+//
+// The precedence of the parameters is defined as follows: Values of parameters
+// marked with [Override] replace all other values, values of parameters marked
+// with [Default] are only used if no other parameters set the key. Parameters of
+// the same precedence must agree on the value of a key, otherwise a
+// [*ConflictError] is returned. Nil parameters are skipped.
+func Merge(parameters ...QueryParameters) (Values, error) {
+	levels := map[precedence]url.Values{
+		defaultPrecedence:  {},
+		regularPrecedence:  {},
+		overridePrecedence: {},
+	}
+	for _, item := range parameters {
+		if item == nil {
+			continue
+		}
+		level := regularPrecedence
+		if p, ok := item.(prioritized); ok {
+			level = p.precedence
+		}
+		encoded, err := item.UrlEncode()
+		if err != nil {
+			return nil, err
+		}
+		values, err := url.ParseQuery(encoded)
+		if err != nil {
+			return nil, err
+		}
+		target := levels[level]
+		for key, value := range values {
+			if existing, ok := target[key]; ok && !equalValues(existing, value) {
+				return nil, &ConflictError{Key: key, Value: existing, Other: value}
+			}
+			target[key] = value
+		}
+	}
+	rsl := Values{}
+	for _, level := range []precedence{defaultPrecedence, regularPrecedence, overridePrecedence} {
+		for key, value := range levels[level] {
+			rsl[key] = value
+		}
+	}
+	return rsl, nil
+}
+
+func equalValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package params_test
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/alex-berlin-tv/gomnia/params"
+)
+
+func TestMergePrecedence(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters []params.QueryParameters
+		want       url.Values
+	}{
+		{
+			name: "regular parameters are combined",
+			parameters: []params.QueryParameters{
+				params.Basic{Limit: 10},
+				params.Custom{"channel": "5"},
+			},
+			want: url.Values{"limit": {"10"}, "channel": {"5"}},
+		},
+		{
+			name: "regular wins over default",
+			parameters: []params.QueryParameters{
+				params.Default(params.Basic{Limit: 100}),
+				params.Basic{Limit: 10},
+			},
+			want: url.Values{"limit": {"10"}},
+		},
+		{
+			name: "default fills missing keys",
+			parameters: []params.QueryParameters{
+				params.Custom{"channel": "5"},
+				params.Default(params.Basic{Limit: 100}),
+			},
+			want: url.Values{"limit": {"100"}, "channel": {"5"}},
+		},
+		{
+			name: "override wins over regular and default",
+			parameters: []params.QueryParameters{
+				params.Override(params.Basic{Start: 200}),
+				params.Basic{Start: 20},
+				params.Default(params.Custom{"start": "0"}),
+			},
+			want: url.Values{"start": {"200"}},
+		},
+		{
+			name: "equal regular values don't conflict",
+			parameters: []params.QueryParameters{
+				params.Custom{"channel": "5"},
+				params.Custom{"channel": "5"},
+			},
+			want: url.Values{"channel": {"5"}},
+		},
+		{
+			name: "nil parameters are skipped",
+			parameters: []params.QueryParameters{
+				nil,
+				params.Custom{"channel": "5"},
+				params.Default(nil),
+				params.Override(nil),
+			},
+			want: url.Values{"channel": {"5"}},
+		},
+		{
+			name: "nothing given",
+			want: url.Values{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := params.Merge(tt.parameters...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(url.Values(got), tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeConflict(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters []params.QueryParameters
+	}{
+		{"regular", []params.QueryParameters{params.Basic{Limit: 10}, params.Custom{"limit": "20"}}},
+		{"default", []params.QueryParameters{params.Default(params.Basic{Limit: 10}), params.Default(params.Custom{"limit": "20"})}},
+		{"override", []params.QueryParameters{params.Override(params.Basic{Limit: 10}), params.Override(params.Custom{"limit": "20"})}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := params.Merge(tt.parameters...)
+			if !errors.Is(err, params.ErrConflict) {
+				t.Fatalf("got error %v, want ErrConflict", err)
+			}
+			var conflict *params.ConflictError
+			if !errors.As(err, &conflict) {
+				t.Fatalf("got error %T, want *ConflictError", err)
+			}
+			if conflict.Key != "limit" || !reflect.DeepEqual(conflict.Value, []string{"10"}) || !reflect.DeepEqual(conflict.Other, []string{"20"}) {
+				t.Fatalf("unexpected conflict %+v", conflict)
+			}
+		})
+	}
+}
+
+func TestMergedValuesEncode(t *testing.T) {
+	merged, err := params.Merge(params.Custom{"b": "2"}, params.Custom{"a": "1 2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := merged.UrlEncode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encoded != "a=1+2&b=2" {
+		t.Fatalf("got %q", encoded)
+	}
+}
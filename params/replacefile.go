@@ -0,0 +1,68 @@
+package params
+
+import (
+	"fmt"
+
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/pasztorpisti/qs"
+)
+
+// Parameters for the replacefile ManagementAPI call which replaces the source file
+// of an item with a remote file. The documentation can be found [here].
+//
+// [here]: https://api.docs.nexx.cloud/management-api/endpoints/management-endpoint#replacefile
+type ReplaceFile struct {
+	// The URL of the new source file. Has to be publicly reachable via HTTP(S).
+	URL string `qs:"url"`
+	// Optional filename of the new source file, if it can't be derived from the URL.
+	Filename string `qs:"filename,omitempty"`
+	// If set to 1, the covers are generated anew from the new file. Otherwise the
+	// existing covers are kept.
+	RegenerateCovers enum.Bool `qs:"regenerateCovers,omitempty"`
+	// If set to 1, the waveform is generated anew from the new file. Otherwise the
+	// existing waveform is kept. Only used for audio.
+	RegenerateWaveform enum.Bool `qs:"regenerateWaveform,omitempty"`
+	// If set to 1, the new file is fetched and processed asynchronously in the
+	// queue of the domain.
+	UseQueue enum.Bool `qs:"useQueue,omitempty"`
+	// Start of the processing as UNIX timestamp if UseQueue is set. Processing
+	// starts immediately if not given.
+	QueueStart int `qs:"queueStart,omitempty"`
+}
+
+func (r ReplaceFile) UrlEncode() (string, error) {
+	return qs.Marshal(&r)
+}
+
+// Checks if the instance is valid for the API. Returns an error with an
+// explanation.
+func (r ReplaceFile) Validate() error {
+	return validateSource(r.URL, r.UseQueue, r.QueueStart)
+}
+
+// Parameters for replacing the source file of an item by uploading a local file (see
+// the ReplaceFileUpload method of the client). The size, checksum and chunking of the
+// file are set by the client.
+type ReplaceUpload struct {
+	// The filename of the new source file including its extension.
+	Filename string `qs:"filename"`
+	// If set to 1, the covers are generated anew from the new file. Otherwise the
+	// existing covers are kept.
+	RegenerateCovers enum.Bool `qs:"regenerateCovers,omitempty"`
+	// If set to 1, the waveform is generated anew from the new file. Otherwise the
+	// existing waveform is kept. Only used for audio.
+	RegenerateWaveform enum.Bool `qs:"regenerateWaveform,omitempty"`
+}
+
+func (r ReplaceUpload) UrlEncode() (string, error) {
+	return qs.Marshal(&r)
+}
+
+// Checks if the instance is valid for the API. Returns an error with an
+// explanation.
+func (r ReplaceUpload) Validate() error {
+	if r.Filename == "" {
+		return fmt.Errorf("filename has to be set")
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+package params
+
+// The typed parameters for the update operation are generated from snapshots of
+// the editable attributes of the streamtypes. Fetch fresh snapshots using the
+// -fetch flag of misc/genupdate if omnia adds new attributes.
+
+//go:generate go run ../misc/genupdate -in ../misc/editable_attributes/videos.json -type UpdateVideo -subject "a video item" -out update_video.go
+//go:generate go run ../misc/genupdate -in ../misc/editable_attributes/audio.json -type UpdateAudio -subject "an audio item" -out update_audio.go
+//go:generate go run ../misc/genupdate -in ../misc/editable_attributes/shows.json -type UpdateShow -subject "a show" -out update_show.go
@@ -0,0 +1,72 @@
+// Code generated by genupdate from ../misc/editable_attributes/audio.json; DO NOT EDIT.
+
+package params
+
+import (
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/pasztorpisti/qs"
+)
+
+// Editable attributes of an audio item for the update operation of the Management
+// API. Only set attributes are sent. Use [Custom] (optionally together with [Merge])
+// to reset an attribute to an empty value or to set attributes unknown to this
+// struct.
+type UpdateAudio struct {
+	// The minimal Age for the Item. Not editable for UGC items.
+	AgeRestriction int `qs:"ageRestriction,omitempty"`
+	// The Type of the Audio Item. Max. 50 characters. Not editable for UGC items.
+	Audiotype string `qs:"audiotype,omitempty"`
+	// The ID of the Category. Not editable for UGC items.
+	Category int `qs:"category,omitempty"`
+	// The ID of the Channel. Not editable for UGC items.
+	Channel int `qs:"channel,omitempty"`
+	// A comma separated List of Content Moderation Aspects. Max. 255 characters.
+	// Not editable for UGC items.
+	ContentModerationAspects string `qs:"contentModerationAspects,omitempty"`
+	// The Copyright of the Item. Max. 255 characters.
+	Copyright string `qs:"copyright,omitempty"`
+	// The Country of Origin. Max. 2 characters. Format: ISO 3166-1 alpha-2. Not
+	// editable for UGC items.
+	Country string `qs:"country,omitempty"`
+	// The Description of the Item, may contain HTML.
+	Description string `qs:"description,omitempty"`
+	// The Episode Number (for Podcast Feeds). Not editable for UGC items.
+	Episode int `qs:"episode,omitempty"`
+	// Mark the Item as explicit (for Podcast Feeds). Not editable for UGC items.
+	ExplicitContent enum.Bool `qs:"explicitContent,omitempty"`
+	// Mark the Item as created for Kids. Not editable for UGC items.
+	ForKids enum.Bool `qs:"forKids,omitempty"`
+	// The ID of the Genre. Not editable for UGC items.
+	Genre int `qs:"genre,omitempty"`
+	// Mark the Item as Evergreen. Not editable for UGC items.
+	IsEvergreen enum.Bool `qs:"isEvergreen,omitempty"`
+	// Mark the Item as Pay Content. Not editable for UGC items.
+	IsPay enum.Bool `qs:"isPay,omitempty"`
+	// Mark the Item as picked. Not editable for UGC items.
+	IsPicked enum.Bool `qs:"isPicked,omitempty"`
+	// A comma separated List of Keywords. Max. 1000 characters.
+	Keywords string `qs:"keywords,omitempty"`
+	// The Language of the Item. Max. 2 characters. Format: ISO 639-1.
+	Language string `qs:"language,omitempty"`
+	// An optional Hint for custom Sort Orders. Max. 100 characters. Not editable
+	// for UGC items.
+	Orderhint string `qs:"orderhint,omitempty"`
+	// The Year of Production. Not editable for UGC items.
+	ProductionYear int `qs:"productionYear,omitempty"`
+	// A custom Reference Number. Max. 100 characters. Not editable for UGC items.
+	Refnr string `qs:"refnr,omitempty"`
+	// The Release Date of the Item. As UNIX timestamp. Not editable for UGC items.
+	Releasedate int `qs:"releasedate,omitempty"`
+	// The Season Number (for Podcast Feeds). Not editable for UGC items.
+	Season int `qs:"season,omitempty"`
+	// The Subtitle of the Item. Max. 255 characters.
+	Subtitle string `qs:"subtitle,omitempty"`
+	// A short Teaser Text. Max. 500 characters.
+	Teaser string `qs:"teaser,omitempty"`
+	// The Title of the Item. Max. 255 characters.
+	Title string `qs:"title,omitempty"`
+}
+
+func (u UpdateAudio) UrlEncode() (string, error) {
+	return qs.Marshal(&u)
+}
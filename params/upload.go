@@ -0,0 +1,43 @@
+package params
+
+import (
+	"fmt"
+
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/pasztorpisti/qs"
+)
+
+// Parameters for a new item created by uploading a local file (see the UploadFile
+// method of the client). The size, checksum and chunking of the file are set by the
+// client.
+type Upload struct {
+	// The filename of the source file including its extension.
+	Filename string `qs:"filename"`
+	// The title of the new item.
+	Title string `qs:"title,omitempty"`
+	// The subtitle of the new item.
+	Subtitle string `qs:"subtitle,omitempty"`
+	// The description of the new item.
+	Description string `qs:"description,omitempty"`
+	// The reference number of the new item.
+	RefNr string `qs:"refnr,omitempty"`
+	// The ID of the channel the new item is assigned to.
+	Channel int `qs:"channel,omitempty"`
+	// If set to 1, the item is published as soon as the processing is finished.
+	AutoPublish enum.Bool `qs:"autoPublish,omitempty"`
+	// Internal notes for the new item.
+	Notes string `qs:"notes,omitempty"`
+}
+
+func (u Upload) UrlEncode() (string, error) {
+	return qs.Marshal(&u)
+}
+
+// Checks if the instance is valid for the API. Returns an error with an
+// explanation.
+func (u Upload) Validate() error {
+	if u.Filename == "" {
+		return fmt.Errorf("filename has to be set")
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package gomnia
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket which limits the number of requests sent to omnia.
+// nexx enforces per-domain request quotas, a limiter prevents the client from
+// exceeding them when the client is used by many goroutines at once. Callers are
+// blocked until a request slot frees up or their context is done. A RateLimiter is
+// safe for concurrent use and can be shared between multiple clients.
+type RateLimiter struct {
+	mutex     sync.Mutex
+	rate      float64
+	burst     float64
+	tokens    float64
+	last      time.Time
+	waiting   int
+	waits     int64
+	waitTotal time.Duration
+	waitMax   time.Duration
+}
+
+// Returns a new rate limiter allowing the given number of requests per second on
+// average. Burst states how many requests can be sent at once after a period of
+// inactivity, it will be at least one.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Statistics on the callers which had to wait for the rate limiter.
+type RateLimiterStats struct {
+	// Number of callers currently blocked by the limiter.
+	Waiting int
+	// Total number of callers which had to wait.
+	Waits int64
+	// Accumulated time callers had to wait.
+	TotalWait time.Duration
+	// Longest time a single caller had to wait.
+	MaxWait time.Duration
+}
+
+// Blocks until a request is allowed to be sent or the context is done. In the
+// latter case the error of the context is returned.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	wait := l.reserve()
+	if wait <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		// No need to wait if the slot would be available after the deadline.
+		l.mutex.Lock()
+		l.tokens++
+		l.mutex.Unlock()
+		return context.DeadlineExceeded
+	}
+	l.mutex.Lock()
+	l.waiting++
+	l.mutex.Unlock()
+	err := sleepCtx(ctx, wait)
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.waiting--
+	if err != nil {
+		// The reserved slot wasn't used, hand it back.
+		l.tokens++
+		return err
+	}
+	l.waits++
+	l.waitTotal += wait
+	if wait > l.waitMax {
+		l.waitMax = wait
+	}
+	return nil
+}
+
+// Returns the current wait statistics of the limiter.
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return RateLimiterStats{
+		Waiting:   l.waiting,
+		Waits:     l.waits,
+		TotalWait: l.waitTotal,
+		MaxWait:   l.waitMax,
+	}
+}
+
+// Takes a token from the bucket and returns the time until the token becomes
+// valid.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.rate <= 0 {
+		return 0
+	}
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// Limit all requests of the client using the given rate limiter. Pass the same
+// limiter to multiple clients of the same domain to share the quota between them.
+func WithRateLimiter(limiter *RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// Limit the requests to the given class of the API using the given rate limiter.
+// Requests have to pass both, the limiter of their class and the limiter set with
+// [WithRateLimiter] (if any).
+func WithClassRateLimiter(class ApiClass, limiter *RateLimiter) ClientOption {
+	return func(c *Client) {
+		limiters := make(map[ApiClass]*RateLimiter, len(c.limiters)+1)
+		for key, value := range c.limiters {
+			limiters[key] = value
+		}
+		limiters[class] = limiter
+		c.limiters = limiters
+	}
+}
+
+// Returns the rate limiter for all requests of the client. Nil if none is set.
+func (o Client) RateLimiter() *RateLimiter {
+	return o.limiter
+}
+
+// Returns the rate limiter for the given API class. Nil if none is set.
+func (o Client) ClassRateLimiter(class ApiClass) *RateLimiter {
+	return o.limiters[class]
+}
+
+// Waits until the rate limiters of the client allow a request to the given class
+// of the API.
+func (o Client) waitForRateLimit(ctx context.Context, class ApiClass) error {
+	if limiter := o.limiters[class]; limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if o.limiter != nil {
+		return o.limiter.Wait(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package gomnia_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	omnia "github.com/alex-berlin-tv/gomnia"
+	"github.com/alex-berlin-tv/gomnia/enum"
+)
+
+// Interval between two tokens of the limiters used in the tests.
+const tokenInterval = 50 * time.Millisecond
+
+func newTestLimiter(burst int) *omnia.RateLimiter {
+	return omnia.NewRateLimiter(float64(time.Second/tokenInterval), burst)
+}
+
+func TestRateLimiterBurst(t *testing.T) {
+	limiter := newTestLimiter(3)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > tokenInterval/2 {
+		t.Fatalf("the burst took %s", elapsed)
+	}
+	if stats := limiter.Stats(); stats.Waits != 0 {
+		t.Fatalf("unexpected waits within the burst %+v", stats)
+	}
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < tokenInterval*8/10 {
+		t.Fatalf("the request after the burst was sent after %s, want about %s", elapsed, tokenInterval)
+	}
+	stats := limiter.Stats()
+	if stats.Waits != 1 || stats.Waiting != 0 || stats.MaxWait <= 0 || stats.TotalWait != stats.MaxWait {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestRateLimiterCancellation(t *testing.T) {
+	limiter := newTestLimiter(1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- limiter.Wait(ctx) }()
+	deadline := time.Now().Add(time.Second)
+	for limiter.Stats().Waiting != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("the caller isn't reported as waiting")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want context.Canceled", err)
+	}
+	if stats := limiter.Stats(); stats.Waiting != 0 || stats.Waits != 0 {
+		t.Fatalf("canceled wait is part of the stats %+v", stats)
+	}
+
+	// A deadline before the next free slot fails immediately.
+	ctx, cancel = context.WithTimeout(context.Background(), tokenInterval/10)
+	defer cancel()
+	start := time.Now()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got error %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > tokenInterval/2 {
+		t.Fatalf("waited %s for a slot after the deadline", elapsed)
+	}
+}
+
+func TestRateLimiterHandsBackTokens(t *testing.T) {
+	limiter := newTestLimiter(1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	// Each of the canceled callers would push the next slot further away if their
+	// tokens weren't handed back.
+	for i := 0; i < 5; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := limiter.Wait(ctx); !errors.Is(err, context.Canceled) {
+			t.Fatalf("got error %v, want context.Canceled", err)
+		}
+	}
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*tokenInterval {
+		t.Fatalf("the next slot was free after %s, want about %s", elapsed, tokenInterval)
+	}
+}
+
+func TestClientRateLimiter(t *testing.T) {
+	srv := &scriptedServer{}
+	limiter := newTestLimiter(1)
+	management := newTestLimiter(1)
+	client := newScriptedClient(t, srv,
+		omnia.WithRateLimiter(limiter),
+		omnia.WithClassRateLimiter(omnia.ManagementApiClass, management),
+	)
+	if client.RateLimiter() != limiter || client.ClassRateLimiter(omnia.ManagementApiClass) != management || client.ClassRateLimiter(omnia.MediaApiClass) != nil {
+		t.Fatal("the limiters aren't returned by the client")
+	}
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.ById(enum.AudioStreamType, 1, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 2*tokenInterval*8/10 {
+		t.Fatalf("three requests took %s, want at least %s", elapsed, 2*tokenInterval)
+	}
+	if stats := limiter.Stats(); stats.Waits != 2 {
+		t.Fatalf("got %d waits, want 2", stats.Waits)
+	}
+	if stats := management.Stats(); stats.Waits != 0 {
+		t.Fatalf("media requests passed the management limiter %+v", stats)
+	}
+
+	if _, err := client.Publish(enum.AudioStreamType, 1); err != nil {
+		t.Fatal(err)
+	}
+	if srv.count() != 4 {
+		t.Fatalf("got %d requests, want 4", srv.count())
+	}
+}
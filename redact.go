@@ -0,0 +1,192 @@
+package gomnia
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Replacement for redacted values in the log output.
+const redactedValue = "[REDACTED]"
+
+// Names of log fields, JSON attributes and query parameters which are always
+// redacted unless redaction is disabled using [WithoutRedaction]. The names are
+// compared case-insensitively.
+var defaultRedactedFields = []string{
+	omniaHeaderXRequestCid,
+	omniaHeaderXRequestToken,
+	"secret",
+	"api_secret",
+	"session_id",
+	// The password of upload links, see params.UploadLink.
+	"code",
+}
+
+// Redact the given fields in addition to the default ones (session id, token,
+// notification secrets and upload link passwords) from the log output. The names are matched against log
+// fields, JSON attributes (at any depth) of logged response bodies and query
+// parameters. Matching is case-insensitive.
+func WithRedactedFields(fields ...string) ClientOption {
+	return func(c *Client) {
+		redacted := make(map[string]struct{}, len(c.redactedFields)+len(fields))
+		for key := range c.redactedFields {
+			redacted[key] = struct{}{}
+		}
+		for _, field := range fields {
+			redacted[strings.ToLower(field)] = struct{}{}
+		}
+		c.redactedFields = redacted
+	}
+}
+
+// Disable the redaction of secrets in the log output. Only use this if the logs
+// never leave your machine.
+func WithoutRedaction() ClientOption {
+	return func(c *Client) {
+		c.noRedaction = true
+	}
+}
+
+// A [Logger] masking secrets before passing the messages to the next logger.
+type redactingLogger struct {
+	next    Logger
+	secrets []string
+	fields  map[string]struct{}
+}
+
+// Returns a logger redacting the secrets of the client and all sensitive fields.
+func (o Client) redactingLogger(next Logger) redactingLogger {
+	fields := make(map[string]struct{}, len(defaultRedactedFields)+len(o.redactedFields))
+	for _, field := range defaultRedactedFields {
+		fields[strings.ToLower(field)] = struct{}{}
+	}
+	for field := range o.redactedFields {
+		fields[field] = struct{}{}
+	}
+	var secrets []string
+	for _, secret := range []string{o.ApiSecret, o.SessionId} {
+		if secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+	return redactingLogger{
+		next:    next,
+		secrets: secrets,
+		fields:  fields,
+	}
+}
+
+// Messages of disabled levels are dropped before the costly redaction.
+func (l redactingLogger) Trace(msg string, fields Fields) {
+	if !l.next.Enabled(TraceLevel) {
+		return
+	}
+	l.next.Trace(l.redactString(msg), l.redactFields(fields))
+}
+
+func (l redactingLogger) Debug(msg string, fields Fields) {
+	if !l.next.Enabled(DebugLevel) {
+		return
+	}
+	l.next.Debug(l.redactString(msg), l.redactFields(fields))
+}
+
+func (l redactingLogger) Enabled(level Level) bool {
+	return l.next.Enabled(level)
+}
+
+func (l redactingLogger) isRedacted(key string) bool {
+	_, ok := l.fields[strings.ToLower(key)]
+	return ok
+}
+
+func (l redactingLogger) redactFields(fields Fields) Fields {
+	if fields == nil {
+		return nil
+	}
+	rsl := make(Fields, len(fields))
+	for key, value := range fields {
+		if l.isRedacted(key) {
+			rsl[key] = redactedValue
+			continue
+		}
+		rsl[key] = l.redactValue(value)
+	}
+	return rsl
+}
+
+func (l redactingLogger) redactValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case string:
+		return l.redactString(value)
+	case map[string]interface{}:
+		return map[string]interface{}(l.redactFields(Fields(value)))
+	case Fields:
+		return l.redactFields(value)
+	case map[string]string:
+		rsl := make(map[string]string, len(value))
+		for key, item := range value {
+			if l.isRedacted(key) {
+				rsl[key] = redactedValue
+			} else {
+				rsl[key] = l.redactString(item)
+			}
+		}
+		return rsl
+	case []interface{}:
+		rsl := make([]interface{}, len(value))
+		for i, item := range value {
+			rsl[i] = l.redactValue(item)
+		}
+		return rsl
+	case nil, bool, int, int64, float64:
+		return value
+	}
+	return l.redactString(fmt.Sprintf("%+v", value))
+}
+
+// Masks the secrets of the client within the string. JSON documents and URL
+// encoded query strings are parsed and the sensitive fields masked.
+func (l redactingLogger) redactString(value string) string {
+	for _, secret := range l.secrets {
+		value = strings.ReplaceAll(value, secret, redactedValue)
+	}
+	trimmed := strings.TrimSpace(value)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var doc interface{}
+		if err := json.Unmarshal([]byte(trimmed), &doc); err == nil {
+			if rsl, err := json.Marshal(l.redactValue(doc)); err == nil {
+				return string(rsl)
+			}
+		}
+		return value
+	}
+	if strings.Contains(value, "=") && !strings.ContainsAny(value, " \n") {
+		return l.redactQuery(value)
+	}
+	return value
+}
+
+// Masks the sensitive parameters of an URL or an URL encoded query string.
+func (l redactingLogger) redactQuery(value string) string {
+	prefix, query := "", value
+	if i := strings.Index(value, "?"); i >= 0 {
+		prefix, query = value[:i+1], value[i+1:]
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return value
+	}
+	changed := false
+	for key := range values {
+		if l.isRedacted(key) {
+			values[key] = []string{redactedValue}
+			changed = true
+		}
+	}
+	if !changed {
+		return value
+	}
+	return prefix + values.Encode()
+}
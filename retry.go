@@ -0,0 +1,211 @@
+package gomnia
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Defines if and how failed API calls are retried. A call is retried if the request
+// failed on the network level, omnia answered with a 5xx status or the request quota
+// was exceeded (429). By default only idempotent requests (GET, HEAD and OPTIONS) are
+// retried, this includes all calls to the Media API. Calls to the Management API
+// using PUT, POST or DELETE (like [Client.Delete] and [Client.RemoveCover]) are only
+// retried if [RetryPolicy.RetryNonIdempotent] is set. DELETE is treated as not
+// idempotent as repeating a deletion which already went through fails.
+//
+// The zero value disables retries. Use [DefaultRetryPolicy] for sane defaults.
+type RetryPolicy struct {
+	// Maximum number of attempts including the first one. A value of one or less
+	// disables retries.
+	MaxAttempts int
+	// Wait time before the first retry.
+	InitialBackoff time.Duration
+	// Upper bound for the wait time between two attempts. Doesn't apply to the
+	// wait time requested by omnia using the Retry-After header.
+	MaxBackoff time.Duration
+	// Factor by which the wait time increases with each attempt. Defaults to 2.
+	Multiplier float64
+	// Fraction (between 0 and 1) of the wait time which is randomized in order
+	// to prevent multiple clients from retrying in lockstep.
+	Jitter float64
+	// Also retry requests which are not idempotent (PUT, POST and DELETE calls to
+	// the Management API). Only enable this if applying a call twice is safe for
+	// your use case.
+	RetryNonIdempotent bool
+	// Called before each retry. Can be used to log or count retries.
+	OnRetry func(RetryEvent)
+}
+
+// Returns a retry policy with three attempts and an exponential backoff starting
+// at 500 milliseconds.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// Information on an upcoming retry, passed to [RetryPolicy.OnRetry].
+type RetryEvent struct {
+	// Number of the failed attempt, starting with 1.
+	Attempt int
+	// HTTP method of the call.
+	Method string
+	// The called URL without the query parameters.
+	Endpoint string
+	// The called operation.
+	Operation string
+	// HTTP status of the failed attempt. Zero if the request failed on the
+	// network level.
+	HttpStatus int
+	// The error of the failed attempt.
+	Err error
+	// Time until the next attempt is made.
+	Wait time.Duration
+}
+
+// Retry the failed calls according to the given policy.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// Whether a request using the given HTTP method may be retried.
+func (p RetryPolicy) allowsMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return p.RetryNonIdempotent
+}
+
+// Wait time before the given retry (starting with 1).
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+	wait := float64(p.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		wait = wait * (1 - jitter + 2*jitter*randFloat())
+	}
+	return time.Duration(wait)
+}
+
+var (
+	jitterRand  = rand.New(rand.NewSource(time.Now().UnixNano()))
+	jitterMutex sync.Mutex
+)
+
+func randFloat() float64 {
+	jitterMutex.Lock()
+	defer jitterMutex.Unlock()
+	return jitterRand.Float64()
+}
+
+// Performs the HTTP request and retries it according to the retry policy of the
+// client.
+func (o Client) send(ctx context.Context, call *Request) (*RawResponse, error) {
+	for attempt := 1; ; attempt++ {
+		if err := o.waitForRateLimit(ctx, call.Class); err != nil {
+			return nil, err
+		}
+		rsl, err := o.sendOnce(ctx, call)
+		if !o.retry.allowsMethod(call.Method) || attempt >= o.retry.MaxAttempts || !shouldRetry(ctx, rsl, err) {
+			return rsl, err
+		}
+		wait := o.retry.backoff(attempt)
+		event := RetryEvent{
+			Attempt:   attempt,
+			Method:    call.Method,
+			Endpoint:  call.Endpoint,
+			Operation: call.Operation,
+			Err:       err,
+		}
+		if rsl != nil {
+			if retryAfter, ok := parseRetryAfter(rsl.Header.Get("Retry-After")); ok {
+				wait = retryAfter
+			}
+			event.HttpStatus = rsl.StatusCode
+			event.Err = newApiError(rsl.StatusCode, rsl.metadata(), call.Method, call.Endpoint, call.Operation, call.StreamType)
+		}
+		event.Wait = wait
+		if o.retry.OnRetry != nil {
+			o.retry.OnRetry(event)
+		}
+		if err := sleepCtx(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Decides whether the outcome of an attempt is a transient failure.
+func shouldRetry(ctx context.Context, rsl *RawResponse, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	if retryableStatus(rsl.StatusCode) {
+		return true
+	}
+	if !rsl.successful() {
+		return false
+	}
+	// omnia might report the failure only within the response metadata.
+	return retryableStatus(rsl.metadata().Status)
+}
+
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// Parses the value of a Retry-After header which is either given in seconds or
+// as a HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	wait := time.Until(date)
+	if wait < 0 {
+		wait = 0
+	}
+	return wait, true
+}
+
+// Waits for the given duration or until the context is done.
+func sleepCtx(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
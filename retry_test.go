@@ -0,0 +1,222 @@
+package gomnia_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	omnia "github.com/alex-berlin-tv/gomnia"
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/alex-berlin-tv/gomnia/params"
+)
+
+// Answers the requests with the given statuses in order, all following requests
+// succeed. The statuses are sent as HTTP status and within the metadata.
+type scriptedServer struct {
+	mutex      sync.Mutex
+	statuses   []int
+	retryAfter string
+	requests   int
+}
+
+func (s *scriptedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	status := http.StatusOK
+	if s.requests < len(s.statuses) {
+		status = s.statuses[s.requests]
+	}
+	s.requests++
+	s.mutex.Unlock()
+	if s.retryAfter != "" && status != http.StatusOK {
+		w.Header().Set("Retry-After", s.retryAfter)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `{"metadata":{"status":%d},"result":{"general":{"ID":1}}}`, status)
+}
+
+func (s *scriptedServer) count() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests
+}
+
+func newScriptedClient(t *testing.T, srv *scriptedServer, opts ...omnia.ClientOption) omnia.Client {
+	t.Helper()
+	httpSrv := httptest.NewServer(srv)
+	t.Cleanup(httpSrv.Close)
+	opts = append(opts, omnia.WithBaseURL(httpSrv.URL))
+	return omnia.NewClient("1", "secret", "session", opts...)
+}
+
+// Returns a policy without jitter recording all retry events.
+func recordingPolicy(events *[]omnia.RetryEvent) omnia.RetryPolicy {
+	return omnia.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		Multiplier:     2,
+		OnRetry:        func(event omnia.RetryEvent) { *events = append(*events, event) },
+	}
+}
+
+func TestRetrySucceedsAfterTransientFailures(t *testing.T) {
+	srv := &scriptedServer{statuses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable}}
+	var events []omnia.RetryEvent
+	client := newScriptedClient(t, srv, omnia.WithRetryPolicy(recordingPolicy(&events)))
+
+	rsl, err := client.ById(enum.AudioStreamType, 1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsl.Result.General.Id != 1 {
+		t.Fatalf("unexpected result %+v", rsl.Result.General)
+	}
+	if srv.count() != 3 || len(events) != 2 {
+		t.Fatalf("got %d requests and %d retries, want 3 and 2", srv.count(), len(events))
+	}
+	for i, event := range events {
+		if event.Attempt != i+1 || event.HttpStatus != http.StatusServiceUnavailable || event.Method != http.MethodGet || event.Operation != "byid" {
+			t.Errorf("unexpected event %+v", event)
+		}
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	srv := &scriptedServer{statuses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}}
+	var events []omnia.RetryEvent
+	client := newScriptedClient(t, srv, omnia.WithRetryPolicy(recordingPolicy(&events)))
+
+	_, err := client.ById(enum.AudioStreamType, 1, nil)
+	var apiErr *omnia.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode() != http.StatusBadGateway {
+		t.Fatalf("got error %v, want the APIError of the last attempt", err)
+	}
+	if srv.count() != 3 {
+		t.Fatalf("got %d requests, want 3", srv.count())
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	srv := &scriptedServer{statuses: []int{500, 500, 500, 500}}
+	var events []omnia.RetryEvent
+	policy := recordingPolicy(&events)
+	policy.MaxAttempts = 4
+	policy.MaxBackoff = 3 * time.Millisecond
+	client := newScriptedClient(t, srv, omnia.WithRetryPolicy(policy))
+
+	if _, err := client.ById(enum.AudioStreamType, 1, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+	want := []time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond}
+	if len(events) != len(want) {
+		t.Fatalf("got %d retries, want %d", len(events), len(want))
+	}
+	for i, event := range events {
+		if event.Wait != want[i] {
+			t.Errorf("retry %d waited %s, want %s", i+1, event.Wait, want[i])
+		}
+	}
+}
+
+func TestRetryJitter(t *testing.T) {
+	srv := &scriptedServer{statuses: []int{500, 500}}
+	var events []omnia.RetryEvent
+	policy := recordingPolicy(&events)
+	policy.MaxAttempts = 2
+	policy.InitialBackoff = 10 * time.Millisecond
+	policy.Jitter = 0.5
+	client := newScriptedClient(t, srv, omnia.WithRetryPolicy(policy))
+
+	if _, err := client.ById(enum.AudioStreamType, 1, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(events) != 1 || events[0].Wait < 5*time.Millisecond || events[0].Wait > 15*time.Millisecond {
+		t.Fatalf("unexpected retries %+v, want a wait between 5ms and 15ms", events)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	srv := &scriptedServer{statuses: []int{http.StatusTooManyRequests}, retryAfter: "0"}
+	var events []omnia.RetryEvent
+	policy := recordingPolicy(&events)
+	// The test times out if the backoff is used instead of the Retry-After header.
+	policy.InitialBackoff = time.Hour
+	client := newScriptedClient(t, srv, omnia.WithRetryPolicy(policy))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.ByIdCtx(ctx, enum.AudioStreamType, 1, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Wait != 0 || events[0].HttpStatus != http.StatusTooManyRequests {
+		t.Fatalf("unexpected retries %+v", events)
+	}
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	calls := map[string]func(omnia.Client) error{
+		http.MethodPost: func(c omnia.Client) error {
+			_, err := c.Publish(enum.AudioStreamType, 1)
+			return err
+		},
+		http.MethodDelete: func(c omnia.Client) error {
+			_, err := c.Delete(enum.AudioStreamType, 1, params.Delete{})
+			return err
+		},
+	}
+	statuses := []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable}
+	for method, call := range calls {
+		t.Run(method, func(t *testing.T) {
+			srv := &scriptedServer{statuses: statuses}
+			var events []omnia.RetryEvent
+			client := newScriptedClient(t, srv, omnia.WithRetryPolicy(recordingPolicy(&events)))
+			err := call(client)
+			var apiErr *omnia.APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode() != http.StatusServiceUnavailable {
+				t.Fatalf("got error %v, want the APIError of the first attempt", err)
+			}
+			if srv.count() != 1 || len(events) != 0 {
+				t.Fatalf("%s was sent %d times by default, want once", method, srv.count())
+			}
+
+			srv = &scriptedServer{statuses: statuses}
+			policy := recordingPolicy(&events)
+			policy.RetryNonIdempotent = true
+			client = newScriptedClient(t, srv, omnia.WithRetryPolicy(policy))
+			if err := call(client); err != nil {
+				t.Fatal(err)
+			}
+			if srv.count() != 3 {
+				t.Fatalf("%s was sent %d times with RetryNonIdempotent, want 3", method, srv.count())
+			}
+			for _, event := range events {
+				if event.Method != method {
+					t.Errorf("unexpected event %+v", event)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryStopsOnCancellation(t *testing.T) {
+	srv := &scriptedServer{statuses: []int{500, 500, 500}}
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := omnia.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Hour,
+		OnRetry:        func(omnia.RetryEvent) { cancel() },
+	}
+	client := newScriptedClient(t, srv, omnia.WithRetryPolicy(policy))
+
+	if _, err := client.ByIdCtx(ctx, enum.AudioStreamType, 1, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want context.Canceled", err)
+	}
+	if srv.count() != 1 {
+		t.Fatalf("got %d requests, want 1", srv.count())
+	}
+}
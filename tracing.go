@@ -0,0 +1,80 @@
+package gomnia
+
+import (
+	"context"
+)
+
+// Tracer opens spans around the calls to the omnia API. It's modeled after the
+// OpenTelemetry API so an adapter can be written in a few lines while this package
+// stays free of any tracing dependency. Set a tracer using [WithTracer], by default
+// no spans are created.
+//
+// A span is opened for each API call with the following attributes:
+//
+//   - omnia.domain_id, omnia.stream_type, omnia.operation, omnia.api_class
+//   - http.method and, once a response was received, http.status_code
+//   - omnia.status and omnia.notice from the [ResponseMetadata]
+//   - omnia.paging.start for calls of listing operations
+//
+// [Client.AllPaged] and [Client.AllPagedConcurrent] open a span for the whole export
+// with a child span for each page, the span of the API call is a child of the page
+// span.
+type Tracer interface {
+	// Starts a new span as child of the span in the context (if any). The returned
+	// context has to contain the new span.
+	Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span)
+}
+
+// A span created by a [Tracer].
+type Span interface {
+	// Adds attributes to the span.
+	SetAttributes(attributes ...Attribute)
+	// Marks the span as failed with the given error.
+	RecordError(err error)
+	// Ends the span.
+	End()
+}
+
+// A key value pair attached to a [Span]. Values are of type string, int, bool or
+// float64.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Trace all calls of the client using the given tracer.
+func WithTracer(tracer Tracer) ClientOption {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
+}
+
+// Returns the tracer of the client.
+func (o Client) tracing() Tracer {
+	if o.tracer == nil {
+		return nopTracer{}
+	}
+	return o.tracer
+}
+
+type nopTracer struct{}
+
+func (nopTracer) Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttributes(attributes ...Attribute) {}
+
+func (nopSpan) RecordError(err error) {}
+
+func (nopSpan) End() {}
+
+// Ends the span and records the error (if any).
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
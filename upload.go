@@ -0,0 +1,473 @@
+package gomnia
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/alex-berlin-tv/gomnia/params"
+)
+
+// Size of the chunks of an upload if not set otherwise with [WithChunkSize].
+const DefaultChunkSize = 8 << 20
+
+// Number of attempts per chunk if not set otherwise with [WithChunkAttempts].
+const DefaultChunkAttempts = 3
+
+// The checksum of the file reported by omnia after an upload doesn't match the
+// local file.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// Session of a chunked upload as reported by omnia.
+type UploadSession struct {
+	// Identifies the upload in all following calls.
+	UploadId string `json:"uploadID"`
+	// Size of all chunks but the last one in bytes.
+	ChunkSize int64 `json:"chunkSize"`
+	// Total number of chunks.
+	Chunks int `json:"chunks"`
+	// Indices (starting with 0) of the chunks received so far.
+	ReceivedChunks []int `json:"receivedChunks"`
+}
+
+// Result of a finished upload.
+type UploadResult struct {
+	ManagementResult
+	// MD5 checksum (hex encoded) of the file as received by omnia.
+	Checksum string `json:"checksum"`
+}
+
+// State of an upload, passed to the callback set with [WithUploadProgress] after the
+// upload was started or resumed and after each chunk.
+type UploadProgress struct {
+	UploadId string
+	// Number of chunks uploaded so far, including chunks uploaded before a resume.
+	CompletedChunks int
+	// Total number of chunks.
+	Chunks int
+	// Number of bytes uploaded so far.
+	Sent int64
+	// Size of the file.
+	Size int64
+}
+
+// Alters the behavior of [Client.UploadFile] and [Client.ReplaceFileUpload].
+type UploadOption func(*uploadConfig)
+
+type uploadConfig struct {
+	chunkSize int64
+	attempts  int
+	backoff   RetryPolicy
+	progress  func(UploadProgress)
+	statePath string
+}
+
+// Split the file into chunks of the given size in bytes. omnia might demand another
+// size, in this case the size of omnia is used.
+func WithChunkSize(size int64) UploadOption {
+	return func(c *uploadConfig) {
+		c.chunkSize = size
+	}
+}
+
+// Try each chunk up to the given number of times before the upload fails. The wait
+// time between two attempts follows the backoff of [DefaultRetryPolicy].
+func WithChunkAttempts(attempts int) UploadOption {
+	return func(c *uploadConfig) {
+		c.attempts = attempts
+	}
+}
+
+// Call the given function whenever the upload made progress.
+func WithUploadProgress(progress func(UploadProgress)) UploadOption {
+	return func(c *uploadConfig) {
+		c.progress = progress
+	}
+}
+
+// Persist the state of the upload to the given file after each chunk. If the file
+// exists when the upload starts and belongs to the same file (same streamtype, item,
+// size and checksum), the upload is resumed and only the missing chunks are sent. The file
+// is removed once the upload is finished.
+func WithUploadState(path string) UploadOption {
+	return func(c *uploadConfig) {
+		c.statePath = path
+	}
+}
+
+// State of an upload persisted with [WithUploadState].
+type uploadState struct {
+	UploadId   string `json:"uploadId"`
+	StreamType string `json:"streamType"`
+	// The item whose source file is replaced, zero for new items.
+	Item      int    `json:"item,omitempty"`
+	Size      int64  `json:"size"`
+	Checksum  string `json:"checksum"`
+	ChunkSize int64  `json:"chunkSize"`
+	Completed []int  `json:"completed"`
+}
+
+func (s uploadState) chunks() int {
+	return int((s.Size + s.ChunkSize - 1) / s.ChunkSize)
+}
+
+func (s uploadState) sent() int64 {
+	var rsl int64
+	last := s.chunks() - 1
+	for _, index := range s.Completed {
+		if index == last {
+			rsl += s.Size - int64(last)*s.ChunkSize
+		} else {
+			rsl += s.ChunkSize
+		}
+	}
+	return rsl
+}
+
+func loadUploadState(path string) (*uploadState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rsl uploadState
+	if err := json.Unmarshal(data, &rsl); err != nil {
+		return nil, fmt.Errorf("invalid upload state %s, %w", path, err)
+	}
+	return &rsl, nil
+}
+
+// Writes the state to a temporary file first so an interrupted write doesn't
+// corrupt an existing state.
+func (s uploadState) save(path string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Creates a new video or audio item by uploading a local file of the given size.
+// The file is split into chunks (see [WithChunkSize]) which are uploaded one after
+// another, each checked by its MD5 checksum and retried on failure (see
+// [WithChunkAttempts]). Once all chunks are uploaded, omnia verifies the checksum of
+// the complete file and creates the item. If the checksum reported by omnia doesn't
+// match the local file, an error wrapping [ErrChecksumMismatch] is returned.
+//
+// Interrupted uploads of large files can be resumed by persisting their state using
+// [WithUploadState]. Example:
+//
+//	file, err := os.Open("master.mp4")
+//	if err != nil {
+//		return err
+//	}
+//	defer file.Close()
+//	info, _ := file.Stat()
+//	rsl, err := client.UploadFile(ctx, enum.VideoStreamType, file, info.Size(),
+//		params.Upload{Filename: "master.mp4", Title: "Master"},
+//		omnia.WithUploadState("master.mp4.upload"),
+//		omnia.WithUploadProgress(func(p omnia.UploadProgress) {
+//			fmt.Printf("%d/%d bytes\n", p.Sent, p.Size)
+//		}),
+//	)
+//
+// The timeout of the client (see [WithTimeout]) applies to each chunk, make sure it's
+// sufficient for the chosen chunk size.
+func (o Client) UploadFile(
+	ctx context.Context,
+	streamType enum.StreamType,
+	file io.ReaderAt,
+	size int64,
+	parameters params.Upload,
+	opts ...UploadOption,
+) (*Response[UploadResult], error) {
+	if err := checkUpload("UploadFile", streamType, size); err != nil {
+		return nil, err
+	}
+	if err := parameters.Validate(); err != nil {
+		return nil, fmt.Errorf("%w, invalid parameters given for UploadFile, %s", ErrValidation, err)
+	}
+	return runUpload(ctx, o, streamType, 0, file, size, parameters, opts, Response[UploadResult]{})
+}
+
+// Replaces the source file of an existing video or audio item by uploading a local
+// file of the given size. The item keeps its ID and all metadata. The upload works
+// the same way as [Client.UploadFile] and accepts the same options. Use
+// [Client.ReplaceFile] to replace the file with a remote one.
+func (o Client) ReplaceFileUpload(
+	ctx context.Context,
+	streamType enum.StreamType,
+	id int,
+	file io.ReaderAt,
+	size int64,
+	parameters params.ReplaceUpload,
+	opts ...UploadOption,
+) (*Response[ProcessingStatus], error) {
+	if err := checkUpload("ReplaceFileUpload", streamType, size); err != nil {
+		return nil, err
+	}
+	if id <= 0 {
+		return nil, fmt.Errorf("%w, invalid id %d given for ReplaceFileUpload", ErrValidation, id)
+	}
+	if err := parameters.Validate(); err != nil {
+		return nil, fmt.Errorf("%w, invalid parameters given for ReplaceFileUpload, %s", ErrValidation, err)
+	}
+	return runUpload(ctx, o, streamType, id, file, size, parameters, opts, Response[ProcessingStatus]{})
+}
+
+// Result of the finish operation of an upload.
+type uploadReceipt interface {
+	// The checksum of the file as received by omnia.
+	receivedChecksum() string
+}
+
+func (r UploadResult) receivedChecksum() string {
+	return r.Checksum
+}
+
+func (s ProcessingStatus) receivedChecksum() string {
+	return s.Checksum
+}
+
+func checkUpload(method string, streamType enum.StreamType, size int64) error {
+	if streamType != enum.VideoStreamType && streamType != enum.AudioStreamType {
+		return fmt.Errorf("%w, %s is only available for videos and audio, %s given", ErrValidation, method, streamType)
+	}
+	if size <= 0 {
+		return fmt.Errorf("%w, invalid size %d given for %s", ErrValidation, size, method)
+	}
+	return nil
+}
+
+// Uploads the file in chunks and finishes the upload. The item is zero for uploads
+// creating a new item, otherwise the source file of the item is replaced.
+func runUpload[T uploadReceipt](
+	ctx context.Context,
+	o Client,
+	streamType enum.StreamType,
+	item int,
+	file io.ReaderAt,
+	size int64,
+	parameters params.QueryParameters,
+	opts []UploadOption,
+	response Response[T],
+) (*Response[T], error) {
+	config := uploadConfig{
+		chunkSize: DefaultChunkSize,
+		attempts:  DefaultChunkAttempts,
+		backoff:   DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.chunkSize <= 0 {
+		return nil, fmt.Errorf("%w, invalid chunk size %d given", ErrValidation, config.chunkSize)
+	}
+
+	checksum, err := md5Of(io.NewSectionReader(file, 0, size))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't compute checksum of file, %w", err)
+	}
+	state, err := o.resumeUpload(ctx, config, streamType, item, size, checksum)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		if state, err = o.startUpload(ctx, config, streamType, item, size, checksum, parameters); err != nil {
+			return nil, err
+		}
+	}
+	o.reportUpload(config, state)
+
+	done := make(map[int]bool, len(state.Completed))
+	for _, index := range state.Completed {
+		done[index] = true
+	}
+	buf := make([]byte, state.ChunkSize)
+	for index := 0; index < state.chunks(); index++ {
+		if done[index] {
+			continue
+		}
+		offset := int64(index) * state.ChunkSize
+		chunk := buf[:minInt64(state.ChunkSize, size-offset)]
+		// The buffer is reused, a short read would send the rest of the previous chunk.
+		if n, err := file.ReadAt(chunk, offset); n < len(chunk) {
+			if err == nil || err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, fmt.Errorf("couldn't read chunk %d, got %d of %d bytes, %w", index, n, len(chunk), err)
+		}
+		if err := o.uploadChunk(ctx, config, streamType, state.UploadId, index, chunk); err != nil {
+			return nil, err
+		}
+		state.Completed = append(state.Completed, index)
+		if config.statePath != "" {
+			if err := state.save(config.statePath); err != nil {
+				return nil, fmt.Errorf("couldn't save upload state, %w", err)
+			}
+		}
+		o.reportUpload(config, state)
+	}
+
+	rsl, err := universalCall(ctx, o, "post", streamType, uploadManagementApiType{}, "finish", []string{url.PathEscape(state.UploadId)}, "", params.Custom{"checksum": checksum}, 0, response)
+	if err != nil {
+		return rsl, err
+	}
+	if config.statePath != "" {
+		if err := os.Remove(config.statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			o.log().Debug("couldn't remove upload state", Fields{"path": config.statePath, "error": err.Error()})
+		}
+	}
+	if received := rsl.Result.receivedChecksum(); received != checksum {
+		return rsl, fmt.Errorf("%w, omnia received %s, local file has %s", ErrChecksumMismatch, received, checksum)
+	}
+	return rsl, nil
+}
+
+// Returns the persisted state of an upload of the same file which is still known to
+// omnia. Returns nil if there is nothing to resume.
+func (o Client) resumeUpload(ctx context.Context, config uploadConfig, streamType enum.StreamType, item int, size int64, checksum string) (*uploadState, error) {
+	if config.statePath == "" {
+		return nil, nil
+	}
+	state, err := loadUploadState(config.statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if state.StreamType != string(streamType) || state.Item != item || state.Size != size || state.Checksum != checksum || state.ChunkSize <= 0 {
+		o.log().Debug("upload state belongs to another file, starting a new upload", Fields{"path": config.statePath})
+		return nil, nil
+	}
+	rsp, err := universalCall(ctx, o, "get", streamType, uploadManagementApiType{}, "status", []string{url.PathEscape(state.UploadId)}, "", nil, 0, Response[UploadSession]{})
+	if errors.Is(err, ErrNotFound) {
+		o.log().Debug("upload is no longer known to omnia, starting a new upload", Fields{"upload_id": state.UploadId})
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// omnia knows best which chunks arrived.
+	state.Completed = append([]int(nil), rsp.Result.ReceivedChunks...)
+	sort.Ints(state.Completed)
+	return state, nil
+}
+
+func (o Client) startUpload(ctx context.Context, config uploadConfig, streamType enum.StreamType, item int, size int64, checksum string, parameters params.QueryParameters) (*uploadState, error) {
+	values := params.Custom{
+		"filesize":  strconv.FormatInt(size, 10),
+		"chunkSize": strconv.FormatInt(config.chunkSize, 10),
+		"checksum":  checksum,
+	}
+	if item != 0 {
+		values["item"] = strconv.Itoa(item)
+	}
+	upload, err := params.Merge(parameters, values)
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := universalCall(ctx, o, "post", streamType, uploadManagementApiType{}, "start", nil, "", upload, 0, Response[UploadSession]{})
+	if err != nil {
+		return nil, err
+	}
+	state := &uploadState{
+		UploadId:   rsp.Result.UploadId,
+		StreamType: string(streamType),
+		Item:       item,
+		Size:       size,
+		Checksum:   checksum,
+		ChunkSize:  config.chunkSize,
+	}
+	if rsp.Result.ChunkSize > 0 {
+		state.ChunkSize = rsp.Result.ChunkSize
+	}
+	if config.statePath != "" {
+		if err := state.save(config.statePath); err != nil {
+			return nil, fmt.Errorf("couldn't save upload state, %w", err)
+		}
+	}
+	return state, nil
+}
+
+// Uploads a single chunk, failed attempts are retried.
+func (o Client) uploadChunk(ctx context.Context, config uploadConfig, streamType enum.StreamType, uploadId string, index int, chunk []byte) error {
+	checksum, _ := md5Of(bytes.NewReader(chunk))
+	args := []string{url.PathEscape(uploadId), strconv.Itoa(index)}
+	for attempt := 1; ; attempt++ {
+		_, err := universalBodyCall(ctx, o, "put", streamType, uploadManagementApiType{}, "chunk", args, "", params.Custom{"checksum": checksum}, 0, chunk, Response[UploadSession]{})
+		if err == nil {
+			return nil
+		}
+		if attempt >= config.attempts || !retryableChunkError(ctx, err) {
+			return fmt.Errorf("couldn't upload chunk %d of %s, %w", index, uploadId, err)
+		}
+		wait := config.backoff.backoff(attempt)
+		o.log().Debug("chunk upload failed, retrying", Fields{"upload_id": uploadId, "chunk": index, "attempt": attempt, "wait": wait.String(), "error": err.Error()})
+		if err := sleepCtx(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// Whether a chunk upload failing with the error should be attempted again. Rejected
+// credentials and unknown uploads are permanent.
+func retryableChunkError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	return !errors.Is(err, ErrUnauthorized) && !errors.Is(err, ErrNotFound)
+}
+
+func (o Client) reportUpload(config uploadConfig, state *uploadState) {
+	if config.progress == nil {
+		return
+	}
+	config.progress(UploadProgress{
+		UploadId:        state.UploadId,
+		CompletedChunks: len(state.Completed),
+		Chunks:          state.chunks(),
+		Sent:            state.sent(),
+		Size:            state.Size,
+	})
+}
+
+// Returns the hex encoded MD5 checksum of the content.
+func md5Of(r io.Reader) (string, error) {
+	hash := md5.New()
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,161 @@
+package gomnia_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+
+	omnia "github.com/alex-berlin-tv/gomnia"
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/alex-berlin-tv/gomnia/gomniatest/fakeserver"
+	"github.com/alex-berlin-tv/gomnia/params"
+)
+
+var uploadContent = []byte("0123456789")
+
+// Records the chunk requests and fails the attempts for which fail returns true.
+type chunkRecorder struct {
+	mutex    sync.Mutex
+	attempts map[int]int
+	sizes    []int
+	fail     func(index, attempt int) bool
+}
+
+func (r *chunkRecorder) middleware(next omnia.RoundTrip) omnia.RoundTrip {
+	return func(ctx context.Context, req *omnia.Request) (*omnia.RawResponse, error) {
+		if req.Operation != "chunk" {
+			return next(ctx, req)
+		}
+		index, _ := strconv.Atoi(req.Args[1])
+		r.mutex.Lock()
+		if r.attempts == nil {
+			r.attempts = map[int]int{}
+		}
+		r.attempts[index]++
+		attempt := r.attempts[index]
+		r.sizes = append(r.sizes, len(req.Body))
+		r.mutex.Unlock()
+		if r.fail != nil && r.fail(index, attempt) {
+			return nil, errors.New("connection reset by peer")
+		}
+		return next(ctx, req)
+	}
+}
+
+func TestUploadFileChunks(t *testing.T) {
+	srv := fakeserver.New()
+	defer srv.Close()
+	rec := &chunkRecorder{}
+	client := srv.Client(omnia.WithMiddleware(rec.middleware))
+
+	var progress []omnia.UploadProgress
+	rsl, err := client.UploadFile(context.Background(), enum.AudioStreamType, bytes.NewReader(uploadContent), int64(len(uploadContent)),
+		params.Upload{Filename: "episode.mp3", Title: "Episode"},
+		omnia.WithChunkSize(4),
+		omnia.WithUploadProgress(func(p omnia.UploadProgress) { progress = append(progress, p) }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rec.sizes, []int{4, 4, 2}) {
+		t.Fatalf("sent chunks of %v bytes, want 4, 4 and 2", rec.sizes)
+	}
+	if len(progress) != 4 || progress[3].CompletedChunks != 3 || progress[3].Chunks != 3 || progress[3].Sent != 10 {
+		t.Fatalf("unexpected progress %+v", progress)
+	}
+	item, ok := srv.Item(enum.AudioStreamType, rsl.Result.Id)
+	if !ok {
+		t.Fatalf("item %d wasn't created", rsl.Result.Id)
+	}
+	if !bytes.Equal(item.File, uploadContent) || item.Filename != "episode.mp3" || item.General.Title != "Episode" {
+		t.Fatalf("unexpected item %+v", item)
+	}
+}
+
+func TestUploadChunkRetry(t *testing.T) {
+	srv := fakeserver.New()
+	defer srv.Close()
+	rec := &chunkRecorder{fail: func(index, attempt int) bool { return index == 1 && attempt == 1 }}
+	client := srv.Client(omnia.WithMiddleware(rec.middleware))
+
+	if _, err := client.UploadFile(context.Background(), enum.AudioStreamType, bytes.NewReader(uploadContent), int64(len(uploadContent)),
+		params.Upload{Filename: "episode.mp3"}, omnia.WithChunkSize(4), omnia.WithChunkAttempts(2),
+	); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[int]int{0: 1, 1: 2, 2: 1}; !reflect.DeepEqual(rec.attempts, want) {
+		t.Fatalf("attempts per chunk %v, want %v", rec.attempts, want)
+	}
+
+	rec = &chunkRecorder{fail: func(index, attempt int) bool { return index == 1 }}
+	client = srv.Client(omnia.WithMiddleware(rec.middleware))
+	if _, err := client.UploadFile(context.Background(), enum.AudioStreamType, bytes.NewReader(uploadContent), int64(len(uploadContent)),
+		params.Upload{Filename: "episode.mp3"}, omnia.WithChunkSize(4), omnia.WithChunkAttempts(1),
+	); err == nil {
+		t.Fatal("expected an error if a chunk fails on its last attempt")
+	}
+	if want := map[int]int{0: 1, 1: 1}; !reflect.DeepEqual(rec.attempts, want) {
+		t.Fatalf("attempts per chunk %v, want %v", rec.attempts, want)
+	}
+}
+
+func TestUploadResume(t *testing.T) {
+	srv := fakeserver.New()
+	defer srv.Close()
+	state := filepath.Join(t.TempDir(), "episode.upload")
+
+	rec := &chunkRecorder{fail: func(index, attempt int) bool { return index == 2 }}
+	client := srv.Client(omnia.WithMiddleware(rec.middleware))
+	if _, err := client.UploadFile(context.Background(), enum.AudioStreamType, bytes.NewReader(uploadContent), int64(len(uploadContent)),
+		params.Upload{Filename: "episode.mp3"}, omnia.WithChunkSize(4), omnia.WithChunkAttempts(1), omnia.WithUploadState(state),
+	); err == nil {
+		t.Fatal("expected the first upload to fail")
+	}
+	if _, err := os.Stat(state); err != nil {
+		t.Fatalf("upload state wasn't saved, %s", err)
+	}
+
+	rec = &chunkRecorder{}
+	client = srv.Client(omnia.WithMiddleware(rec.middleware))
+	rsl, err := client.UploadFile(context.Background(), enum.AudioStreamType, bytes.NewReader(uploadContent), int64(len(uploadContent)),
+		params.Upload{Filename: "episode.mp3"}, omnia.WithChunkSize(4), omnia.WithUploadState(state),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[int]int{2: 1}; !reflect.DeepEqual(rec.attempts, want) {
+		t.Fatalf("resumed upload sent chunks %v, want only chunk 2", rec.attempts)
+	}
+	if _, err := os.Stat(state); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("upload state wasn't removed, %v", err)
+	}
+	item, _ := srv.Item(enum.AudioStreamType, rsl.Result.Id)
+	if !bytes.Equal(item.File, uploadContent) {
+		t.Fatalf("item has file %q, want %q", item.File, uploadContent)
+	}
+}
+
+func TestUploadShortRead(t *testing.T) {
+	srv := fakeserver.New()
+	defer srv.Close()
+	rec := &chunkRecorder{}
+	client := srv.Client(omnia.WithMiddleware(rec.middleware))
+
+	// The file is shorter than the given size, the third chunk can't be read completely.
+	_, err := client.UploadFile(context.Background(), enum.AudioStreamType, bytes.NewReader(uploadContent), int64(len(uploadContent))+3,
+		params.Upload{Filename: "episode.mp3"}, omnia.WithChunkSize(4),
+	)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("got error %v, want io.ErrUnexpectedEOF", err)
+	}
+	if !reflect.DeepEqual(rec.sizes, []int{4, 4}) {
+		t.Fatalf("sent chunks of %v bytes, want only the complete ones", rec.sizes)
+	}
+}
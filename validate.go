@@ -0,0 +1,211 @@
+package gomnia
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/alex-berlin-tv/gomnia/params"
+)
+
+// Validate the parameters of [Client.Update] against the editable attributes of the
+// streamtype before sending them. See [Client.ValidateUpdate] for details. As the
+// client doesn't know whether an item is user generated, the UGC restrictions are
+// only checked when calling [Client.ValidateUpdate] directly.
+func WithUpdateValidation() ClientOption {
+	return func(c *Client) {
+		c.validateUpdates = true
+	}
+}
+
+// Caches the editable attributes per streamtype. Shared between all copies of a
+// client.
+type attributeCache struct {
+	mutex   sync.Mutex
+	entries map[enum.StreamType]EditableAttributesResponse
+}
+
+func newAttributeCache() *attributeCache {
+	return &attributeCache{
+		entries: map[enum.StreamType]EditableAttributesResponse{},
+	}
+}
+
+// Returns the editable attributes of the streamtype. They are fetched from omnia on
+// the first call and cached for the lifetime of the client. Only clients created
+// with [NewClient] or [OmniaFromFile] have a cache, clients created as struct
+// literal fetch the attributes on every call.
+func (o Client) CachedEditableAttributes(ctx context.Context, streamType enum.StreamType) (EditableAttributesResponse, error) {
+	if o.attributes != nil {
+		o.attributes.mutex.Lock()
+		rsl, ok := o.attributes.entries[streamType]
+		o.attributes.mutex.Unlock()
+		if ok {
+			return rsl, nil
+		}
+	}
+	rsp, err := o.EditableAttributesCtx(ctx, streamType)
+	if err != nil {
+		return nil, err
+	}
+	if o.attributes != nil {
+		o.attributes.mutex.Lock()
+		o.attributes.entries[streamType] = rsp.Result
+		o.attributes.mutex.Unlock()
+	}
+	return rsp.Result, nil
+}
+
+// Drops the cached editable attributes of all streamtypes. They will be fetched
+// again on the next validation.
+func (o Client) ClearEditableAttributesCache() {
+	if o.attributes == nil {
+		return
+	}
+	o.attributes.mutex.Lock()
+	defer o.attributes.mutex.Unlock()
+	o.attributes.entries = map[enum.StreamType]EditableAttributesResponse{}
+}
+
+// Checks the parameters for an update of an item against the editable attributes
+// of the streamtype (see [Client.EditableAttributes]) without changing anything.
+// Reported are unknown attributes, values exceeding the maximal length, values not
+// matching the type or format of an attribute and, if ugc is set, attributes which
+// aren't editable for user generated items. All invalid attributes are returned
+// at once as [*ValidationError]. Example:
+//
+//	err := client.ValidateUpdate(ctx, enum.AudioStreamType, params.UpdateAudio{
+//		Title: title,
+//	}, false)
+//	var invalid *omnia.ValidationError
+//	if errors.As(err, &invalid) {
+//		for _, field := range invalid.Fields {
+//			fmt.Println(field.Attribute, field.Reason)
+//		}
+//	}
+func (o Client) ValidateUpdate(ctx context.Context, streamType enum.StreamType, parameters params.QueryParameters, ugc bool) error {
+	attributes, err := o.CachedEditableAttributes(ctx, streamType)
+	if err != nil {
+		return fmt.Errorf("couldn't fetch editable attributes for %s, %w", streamType, err)
+	}
+	return validateAttributes(attributes, streamType, parameters, ugc)
+}
+
+// The invalid value of a single attribute.
+type FieldError struct {
+	// Name of the attribute.
+	Attribute string
+	// The rejected value.
+	Value string
+	// Why the value was rejected.
+	Reason string
+}
+
+func (e FieldError) String() string {
+	return fmt.Sprintf("%s %s", e.Attribute, e.Reason)
+}
+
+// Returned by [Client.ValidateUpdate] if one or more attributes are invalid. Can be
+// used with [errors.Is] and [ErrValidation].
+type ValidationError struct {
+	StreamType enum.StreamType
+	// All invalid attributes ordered by their name.
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		parts[i] = field.String()
+	}
+	return fmt.Sprintf("%s, %d invalid attribute(s) for %s: %s", ErrValidation, len(e.Fields), e.StreamType, strings.Join(parts, "; "))
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Patterns for the formats of attributes known to this package. Values of other
+// formats aren't checked.
+var attributeFormats = map[string]*regexp.Regexp{
+	"iso 639-1":          regexp.MustCompile(`^[a-z]{2}$`),
+	"iso 3166-1 alpha-2": regexp.MustCompile(`^[A-Za-z]{2}$`),
+	"email":              regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`),
+	"date":               regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`),
+	"color":              regexp.MustCompile(`^#?[0-9A-Fa-f]{6}$`),
+}
+
+func validateAttributes(attributes EditableAttributesResponse, streamType enum.StreamType, parameters params.QueryParameters, ugc bool) error {
+	if parameters == nil {
+		return nil
+	}
+	encoded, err := parameters.UrlEncode()
+	if err != nil {
+		return err
+	}
+	values, err := url.ParseQuery(encoded)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	rsl := &ValidationError{StreamType: streamType}
+	for _, key := range keys {
+		property, ok := attributes[key]
+		for _, value := range values[key] {
+			reason := ""
+			if !ok {
+				reason = "is not an editable attribute"
+			} else {
+				reason = checkAttribute(property, value, ugc)
+			}
+			if reason != "" {
+				rsl.Fields = append(rsl.Fields, FieldError{Attribute: key, Value: value, Reason: reason})
+			}
+		}
+	}
+	if len(rsl.Fields) > 0 {
+		return rsl
+	}
+	return nil
+}
+
+// Returns why the value is invalid for the attribute, empty if it's valid.
+func checkAttribute(property EditableAttributesProperties, value string, ugc bool) string {
+	if ugc && property.AllowedInUgc == 0 {
+		return "is not editable for UGC items"
+	}
+	switch strings.ToLower(property.Type) {
+	case "int", "integer", "number", "timestamp":
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Sprintf("has to be an integer, got %q", value)
+		}
+	case "float", "decimal":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Sprintf("has to be a number, got %q", value)
+		}
+	case "bool", "boolean":
+		if value != string(enum.NoBool) && value != string(enum.YesBool) {
+			return fmt.Sprintf("has to be 0 or 1, got %q", value)
+		}
+	default:
+		if property.MaxLength > 0 && utf8.RuneCountInString(value) > property.MaxLength {
+			return fmt.Sprintf("exceeds the maximal length of %d characters", property.MaxLength)
+		}
+	}
+	if pattern, ok := attributeFormats[strings.ToLower(property.Format)]; ok && !pattern.MatchString(value) {
+		return fmt.Sprintf("doesn't match the format %s, got %q", property.Format, value)
+	}
+	return ""
+}
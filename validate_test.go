@@ -0,0 +1,131 @@
+package gomnia
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alex-berlin-tv/gomnia/enum"
+	"github.com/alex-berlin-tv/gomnia/params"
+)
+
+var testAttributes = EditableAttributesResponse{
+	"title":    {Type: "string", MaxLength: 5, AllowedInUgc: 1},
+	"channel":  {Type: "int"},
+	"rating":   {Type: "float", AllowedInUgc: 1},
+	"isPicked": {Type: "bool", AllowedInUgc: 1},
+	"language": {Type: "string", Format: "ISO 639-1", AllowedInUgc: 1},
+	"contact":  {Type: "string", Format: "email", AllowedInUgc: 1},
+}
+
+func TestValidateAttributes(t *testing.T) {
+	tests := []struct {
+		name       string
+		parameters params.QueryParameters
+		ugc        bool
+		invalid    []string
+	}{
+		{"no parameters", nil, false, nil},
+		{"valid", params.Custom{"title": "Hallo", "channel": "5", "rating": "1.5", "isPicked": "1", "language": "de", "contact": "a@b.de"}, false, nil},
+		{"unknown attribute", params.Custom{"title": "Hallo", "foo": "bar"}, false, []string{"foo"}},
+		{"length in characters", params.Custom{"title": "Größe"}, false, nil},
+		{"too long", params.Custom{"title": "Größen"}, false, []string{"title"}},
+		{"integer", params.Custom{"channel": "five"}, false, []string{"channel"}},
+		{"float", params.Custom{"rating": "good"}, false, []string{"rating"}},
+		{"bool", params.Custom{"isPicked": "2"}, false, []string{"isPicked"}},
+		{"language format", params.Custom{"language": "deu"}, false, []string{"language"}},
+		{"email format", params.Custom{"contact": "nobody"}, false, []string{"contact"}},
+		{"not editable for UGC", params.Custom{"title": "Hallo", "channel": "5"}, true, []string{"channel"}},
+		{"all invalid attributes sorted", params.Custom{"title": "Too long", "foo": "bar", "channel": "x"}, false, []string{"channel", "foo", "title"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAttributes(testAttributes, enum.AudioStreamType, tt.parameters, tt.ugc)
+			if tt.invalid == nil {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrValidation) {
+				t.Fatalf("got error %v, want ErrValidation", err)
+			}
+			var invalid *ValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("got error %T, want *ValidationError", err)
+			}
+			var got []string
+			for _, field := range invalid.Fields {
+				got = append(got, field.Attribute)
+			}
+			if !reflect.DeepEqual(got, tt.invalid) {
+				t.Fatalf("got invalid attributes %v, want %v", got, tt.invalid)
+			}
+		})
+	}
+}
+
+// Answers all requests with the test attributes and counts them.
+func newAttributeServer(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"metadata":{"status":200},"result":{"title":{"type":"string","maxlength":5,"allowedInUGC":1}}}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestCachedEditableAttributes(t *testing.T) {
+	srv, requests := newAttributeServer(t)
+	client := NewClient("1", "secret", "session", WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := client.ValidateUpdate(ctx, enum.AudioStreamType, params.Custom{"title": "Hallo"}, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Copies of the client share the cache.
+	copied := client
+	if _, err := copied.CachedEditableAttributes(ctx, enum.AudioStreamType); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Fatalf("fetched the attributes %d times, want once", n)
+	}
+	if _, err := client.CachedEditableAttributes(ctx, enum.VideoStreamType); err != nil {
+		t.Fatal(err)
+	}
+	client.ClearEditableAttributesCache()
+	if _, err := client.CachedEditableAttributes(ctx, enum.AudioStreamType); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(requests); n != 3 {
+		t.Fatalf("fetched the attributes %d times, want 3", n)
+	}
+}
+
+func TestCachedEditableAttributesOfLiteral(t *testing.T) {
+	srv, requests := newAttributeServer(t)
+	ctx := context.Background()
+	client := Client{DomainId: "1", ApiSecret: "secret", SessionId: "session", baseUrl: srv.URL}
+
+	// Clients created as struct literal have no cache.
+	for i := 0; i < 2; i++ {
+		if _, err := client.CachedEditableAttributes(ctx, enum.AudioStreamType); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client.ClearEditableAttributesCache()
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Fatalf("fetched the attributes %d times, want 2", n)
+	}
+}